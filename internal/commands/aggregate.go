@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// FleetAggregate is the sum of current metrics across every server.
+// Servers without metrics count toward Servers, Online, and Offline but
+// are left out of every other total and counted in Skipped.
+type FleetAggregate struct {
+	Servers        int      `json:"servers" yaml:"servers"`
+	Online         int      `json:"online" yaml:"online"`
+	Offline        int      `json:"offline" yaml:"offline"`
+	WithMetrics    int      `json:"with_metrics" yaml:"with_metrics"`
+	Skipped        int      `json:"skipped" yaml:"skipped"`
+	AvgCPUUsage    *float64 `json:"avg_cpu_usage" yaml:"avg_cpu_usage"`
+	MemoryUsed     int64    `json:"memory_used" yaml:"memory_used"`
+	MemoryTotal    int64    `json:"memory_total" yaml:"memory_total"`
+	DiskUsed       int64    `json:"disk_used" yaml:"disk_used"`
+	DiskTotal      int64    `json:"disk_total" yaml:"disk_total"`
+	TotalProcesses int      `json:"total_processes" yaml:"total_processes"`
+}
+
+// metricsCmd groups fleet-wide metrics commands
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Fleet-wide metrics",
+	Long:  `Commands that summarize metrics across all of your servers.`,
+}
+
+var metricsAggregateCmd = &cobra.Command{
+	Use:   "aggregate",
+	Short: "Show metric totals across all servers",
+	Long: `Show totals across the whole fleet: memory and disk used/total,
+average CPU usage, total processes, and how many servers are online or
+offline.
+
+Metrics are fetched for any server the list doesn't include them for.
+Servers that still have no metrics are left out of the totals and
+averages; the number skipped is reported. Every server that isn't online
+counts as offline.
+
+Examples:
+  vstats metrics aggregate
+  vstats metrics aggregate -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireLogin(); err != nil {
+			return err
+		}
+
+		client := NewClient()
+		servers, err := client.ListServers()
+		if err != nil {
+			return fmt.Errorf("failed to list servers: %w", err)
+		}
+		fillServerMetrics(client, servers)
+		agg := aggregateFleet(servers)
+
+		switch outputFmt {
+		case "json":
+			return OutputJSON(agg)
+		case "jsonl":
+			return OutputJSONL(agg)
+		case "yaml":
+			return OutputYAML(agg)
+		case "csv":
+			return OutputCSV(agg)
+		case "template":
+			return OutputTemplate(agg)
+		default:
+			printFleetAggregate(agg)
+		}
+		return nil
+	},
+}
+
+// fillServerMetrics fetches current metrics in parallel for servers the
+// list returned without them. Servers whose metrics can't be fetched are
+// left with nil Metrics.
+func fillServerMetrics(client *Client, servers []Server) {
+	forEachParallel(len(servers), concurrency, func(i int) {
+		if servers[i].Metrics != nil {
+			return
+		}
+		if resp, err := client.GetServerMetrics(servers[i].ID); err == nil {
+			servers[i].Metrics = resp.Metrics
+		}
+	})
+}
+
+// aggregateFleet sums the metrics of servers, skipping those without any
+func aggregateFleet(servers []Server) FleetAggregate {
+	agg := FleetAggregate{Servers: len(servers)}
+	var cpuSum float64
+	cpuCount := 0
+	for _, s := range servers {
+		switch strings.ToLower(s.Status) {
+		case "online", "active", "healthy":
+			agg.Online++
+		default:
+			agg.Offline++
+		}
+
+		m := s.Metrics
+		if m == nil {
+			agg.Skipped++
+			continue
+		}
+		agg.WithMetrics++
+		if m.CPUUsage != nil {
+			cpuSum += *m.CPUUsage
+			cpuCount++
+		}
+		if m.MemoryUsed != nil && m.MemoryTotal != nil {
+			agg.MemoryUsed += *m.MemoryUsed
+			agg.MemoryTotal += *m.MemoryTotal
+		}
+		if m.DiskUsed != nil && m.DiskTotal != nil {
+			agg.DiskUsed += *m.DiskUsed
+			agg.DiskTotal += *m.DiskTotal
+		}
+		if m.ProcessCount != nil {
+			agg.TotalProcesses += *m.ProcessCount
+		}
+	}
+	if cpuCount > 0 {
+		avg := cpuSum / float64(cpuCount)
+		agg.AvgCPUUsage = &avg
+	}
+	return agg
+}
+
+// printFleetAggregate renders the fleet totals as a table
+func printFleetAggregate(agg FleetAggregate) {
+	usage := func(used, total int64) string {
+		if total <= 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%s / %s (%.1f%%)", formatBytes(used), formatBytes(total), float64(used)/float64(total)*100)
+	}
+
+	table := NewTable("METRIC", "VALUE")
+	table.AddRow("Servers", fmt.Sprintf("%d", agg.Servers))
+	table.AddRow("Online", fmt.Sprintf("%d", agg.Online))
+	table.AddRow("Offline", fmt.Sprintf("%d", agg.Offline))
+	table.AddRow("Avg CPU", ptrFloat(agg.AvgCPUUsage))
+	table.AddRow("Memory", usage(agg.MemoryUsed, agg.MemoryTotal))
+	table.AddRow("Disk", usage(agg.DiskUsed, agg.DiskTotal))
+	table.AddRow("Processes", fmt.Sprintf("%d", agg.TotalProcesses))
+	table.Render()
+
+	if agg.Skipped > 0 && !quiet {
+		fmt.Printf("\n%d of %d server(s) had no metrics and were left out of the totals.\n", agg.Skipped, agg.Servers)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(metricsCmd)
+	metricsCmd.AddCommand(metricsAggregateCmd)
+}
@@ -2,7 +2,6 @@ package commands
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -11,7 +10,6 @@ import (
 
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
-	"gopkg.in/yaml.v3"
 )
 
 // loginCmd represents the login command
@@ -22,9 +20,28 @@ var loginCmd = &cobra.Command{
 
 You can get your token from the vStats Cloud dashboard.
 
+With --browser, no token needs to be copied: the CLI prints a short code
+and a URL, opens the browser, and waits until you approve the login on
+the vStats Cloud site. The token's lifetime comes from the server.
+
+Organizations using single sign-on can log in through their OIDC identity
+provider instead. Pass --oidc-issuer (or set oidc_issuer in the config) to
+open the provider's sign-in page in a browser; the CLI receives the result
+on a local loopback address and exchanges it for a vStats token. Without
+an issuer configured, the token prompt is used.
+
+With --use-keyring (or credential_store set to keyring) the token is kept
+in the system secret store (macOS Keychain, Windows Credential Manager, or
+libsecret on Linux) rather than in plaintext in the config file. If the
+keyring can't be used, the token is stored in the config file as before.
+
 Examples:
   vstats login                    # Interactive login
-  vstats login --token <token>    # Login with token directly`,
+  vstats login --token <token>    # Login with token directly
+  vstats login --browser          # Approve the login in a browser
+  vstats login --oidc-issuer https://sso.example.com   # SSO login
+  vstats login --use-keyring      # Keep the token in the OS keyring
+  vstats login --refresh          # Extend the current login`,
 	RunE: runLogin,
 }
 
@@ -32,17 +49,57 @@ var loginToken string
 
 func init() {
 	loginCmd.Flags().StringVarP(&loginToken, "token", "t", "", "authentication token")
+	loginCmd.Flags().Bool("browser", false, "log in by approving a code in the browser (device flow)")
+	loginCmd.Flags().String("oidc-issuer", "", "log in through this OIDC issuer (SSO)")
+	loginCmd.Flags().Bool("refresh", false, "re-verify the stored token and extend its expiry")
+	loginCmd.Flags().Bool("use-keyring", false, "store the token in the system keyring instead of the config file")
+	loginCmd.Flags().String("oidc-client-id", "", "OIDC client ID (default: oidc_client_id config or vstats-cli)")
+	logoutCmd.Flags().Bool("revoke", false, "invalidate the token server-side before logging out")
+	whoamiCmd.Flags().BoolP("verbose", "v", false, "also show login expiry and the cloud URL")
 }
 
 func runLogin(cmd *cobra.Command, args []string) error {
+	if refresh, _ := cmd.Flags().GetBool("refresh"); refresh {
+		return refreshLogin()
+	}
+
 	token := loginToken
+	var refreshToken string
+	var expiresAt int64
+
+	browser, _ := cmd.Flags().GetBool("browser")
+	if browser && token == "" {
+		fmt.Printf("Logging in to %s\n\n", currentCloudURL())
+		resp, err := runDeviceLogin()
+		if err != nil {
+			return fmt.Errorf("browser login failed: %w", err)
+		}
+		token, refreshToken = resp.Token, resp.RefreshToken
+		if resp.ExpiresIn > 0 {
+			expiresAt = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second).Unix()
+		}
+	}
+
+	issuerFlag, _ := cmd.Flags().GetString("oidc-issuer")
+	if issuer := oidcIssuer(issuerFlag); token == "" && issuer != "" {
+		clientID, _ := cmd.Flags().GetString("oidc-client-id")
+		if clientID == "" {
+			clientID = cfg.OIDCClientID
+		}
+		fmt.Printf("Logging in through %s\n\n", issuer)
+		resp, err := runOIDCLogin(issuer, clientID)
+		if err != nil {
+			return fmt.Errorf("SSO login failed: %w", err)
+		}
+		token, refreshToken, expiresAt = resp.Token, resp.RefreshToken, resp.ExpiresAt
+	}
 
 	// If no token provided, prompt for it
 	if token == "" {
 		fmt.Println("Login to vStats Cloud")
 		fmt.Println("=====================")
 		fmt.Println()
-		fmt.Println("You can get your token from: " + cfg.CloudURL)
+		fmt.Println("You can get your token from: " + currentCloudURL())
 		fmt.Println()
 		fmt.Print("Enter your token: ")
 
@@ -70,11 +127,7 @@ func runLogin(cmd *cobra.Command, args []string) error {
 
 	// Verify the token
 	fmt.Println("Verifying token...")
-	client := &Client{
-		BaseURL:    cfg.CloudURL,
-		Token:      token,
-		HTTPClient: NewClient().HTTPClient,
-	}
+	client := newClientFor(currentCloudURL(), token)
 
 	resp, err := client.VerifyToken()
 	if err != nil {
@@ -86,9 +139,20 @@ func runLogin(cmd *cobra.Command, args []string) error {
 	}
 
 	// Save the token
+	if useKeyringFlag, _ := cmd.Flags().GetBool("use-keyring"); useKeyringFlag {
+		cfg.CredentialStore = credentialStoreKeyring
+		keyringLoaded = true
+	}
+	// The token belongs to the host it was verified against, so a
+	// --cloud-url or VSTATS_CLOUD_URL override is saved along with it
+	cfg.CloudURL = currentCloudURL()
 	cfg.Token = token
 	cfg.Username = resp.Username
-	cfg.ExpiresAt = time.Now().Add(7 * 24 * time.Hour).Unix() // JWT typically expires in 7 days
+	cfg.RefreshToken = refreshToken
+	cfg.ExpiresAt = expiresAt
+	if cfg.ExpiresAt == 0 {
+		cfg.ExpiresAt = time.Now().Add(tokenLifetime).Unix()
+	}
 
 	if err := SaveConfig(); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
@@ -104,15 +168,30 @@ func runLogin(cmd *cobra.Command, args []string) error {
 var logoutCmd = &cobra.Command{
 	Use:   "logout",
 	Short: "Logout from vStats Cloud",
-	Long:  `Logout from vStats Cloud and remove stored credentials.`,
+	Long: `Logout from vStats Cloud and remove stored credentials.
+
+Use --revoke to also invalidate the token on the server, so it cannot be
+used again even if it was copied elsewhere. The local credentials are
+removed even if revocation fails.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !IsLoggedIn() {
 			fmt.Println("Not logged in")
 			return nil
 		}
 
+		revoke, _ := cmd.Flags().GetBool("revoke")
+		if revoke {
+			if err := NewClient().RevokeToken(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to revoke token: %v\n", err)
+			} else {
+				fmt.Println("✓ Token revoked")
+			}
+		}
+
 		username := cfg.Username
+		keyringLoaded = true // clear the keyring entries even if reading them failed
 		cfg.Token = ""
+		cfg.RefreshToken = ""
 		cfg.Username = ""
 		cfg.ExpiresAt = 0
 
@@ -125,14 +204,28 @@ var logoutCmd = &cobra.Command{
 	},
 }
 
+// WhoamiInfo is the account summary shown by whoami
+type WhoamiInfo struct {
+	CurrentUserResponse `yaml:",inline"`
+	IsPro               *bool      `json:"is_pro,omitempty" yaml:"is_pro,omitempty"`
+	WebCount            *int       `json:"web_count,omitempty" yaml:"web_count,omitempty"`
+	WebLimit            *int       `json:"web_limit,omitempty" yaml:"web_limit,omitempty"`
+	ExpiresAt           *time.Time `json:"expires_at,omitempty" yaml:"expires_at,omitempty"`
+	CloudURL            string     `json:"cloud_url,omitempty" yaml:"cloud_url,omitempty"`
+}
+
 // whoamiCmd represents the whoami command
 var whoamiCmd = &cobra.Command{
 	Use:   "whoami",
 	Short: "Show current user information",
-	Long:  `Display information about the currently logged in user.`,
+	Long: `Display information about the currently logged in user, including
+server and web instance usage against the plan limits.
+
+Use --verbose to also show when the login expires and which cloud URL is
+in use.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if !IsLoggedIn() {
-			return fmt.Errorf("not logged in. Run 'vstats login' first")
+		if err := requireLogin(); err != nil {
+			return err
 		}
 
 		client := NewClient()
@@ -141,13 +234,41 @@ var whoamiCmd = &cobra.Command{
 			return fmt.Errorf("failed to get user info: %w", err)
 		}
 
+		// Plan and web usage are extra detail; show the user without them
+		// when they can't be fetched
+		info := WhoamiInfo{CurrentUserResponse: *resp}
+		if plan, err := client.GetUserPlan(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to get plan: %v\n", err)
+		} else {
+			info.IsPro, info.WebLimit = &plan.IsPro, &plan.MaxWebApps
+		}
+		if instances, err := client.ListWebInstances(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to list web instances: %v\n", err)
+		} else {
+			count := len(instances)
+			info.WebCount = &count
+		}
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		if verbose {
+			info.CloudURL = currentCloudURL()
+			// The stored expiry describes the saved token, not VSTATS_TOKEN
+			if cfg.ExpiresAt != 0 && !tokenFromEnv() {
+				expires := time.Unix(cfg.ExpiresAt, 0)
+				info.ExpiresAt = &expires
+			}
+		}
+
 		switch outputFmt {
 		case "json":
-			data, _ := json.MarshalIndent(resp, "", "  ")
-			fmt.Println(string(data))
+			return OutputJSON(info)
+		case "jsonl":
+			return OutputJSONL(info)
 		case "yaml":
-			data, _ := yaml.Marshal(resp)
-			fmt.Print(string(data))
+			return OutputYAML(info)
+		case "csv":
+			return OutputCSV(info)
+		case "template":
+			return OutputTemplate(info)
 		default:
 			user := resp.User
 			fmt.Println("Current User")
@@ -156,19 +277,81 @@ var whoamiCmd = &cobra.Command{
 			if user.Email != nil {
 				fmt.Printf("Email:        %s\n", *user.Email)
 			}
-			fmt.Printf("Plan:         %s\n", user.Plan)
+			plan := user.Plan
+			if info.IsPro != nil && *info.IsPro {
+				plan += " (Pro)"
+			}
+			fmt.Printf("Plan:         %s\n", plan)
 			fmt.Printf("Servers:      %d / %d\n", resp.ServerCount, resp.ServerLimit)
+			if info.WebCount != nil && info.WebLimit != nil {
+				fmt.Printf("Web apps:     %d / %s\n", *info.WebCount, formatLimit(*info.WebLimit))
+			}
 			fmt.Printf("Status:       %s\n", user.Status)
+			if verbose {
+				switch {
+				case info.ExpiresAt != nil:
+					fmt.Printf("Expires:      %s\n", formatTime(info.ExpiresAt))
+				case tokenFromEnv():
+					fmt.Printf("Expires:      unknown (token from %s)\n", envToken)
+				default:
+					fmt.Println("Expires:      unknown")
+				}
+				fmt.Printf("Cloud URL:    %s\n", info.CloudURL)
+			}
 		}
 		return nil
 	},
 }
 
-// requireLogin checks if the user is logged in and returns an error if not
+// tokenExpiryWarning is how close to expiry requireLogin starts warning
+const tokenExpiryWarning = 24 * time.Hour
+
+// tokenLifetime is how long a verified token is assumed to stay valid
+const tokenLifetime = 7 * 24 * time.Hour // JWT typically expires in 7 days
+
+// requireLogin checks if the user is logged in and returns an error if not.
+// An expired token is an error; one expiring soon prints a warning.
 func requireLogin() error {
 	if !IsLoggedIn() {
 		return fmt.Errorf("not logged in. Run 'vstats login' first")
 	}
+	// The stored expiry describes the saved token, not VSTATS_TOKEN
+	if cfg.ExpiresAt == 0 || tokenFromEnv() {
+		return nil
+	}
+
+	remaining := time.Until(time.Unix(cfg.ExpiresAt, 0))
+	switch {
+	case remaining <= 0:
+		return fmt.Errorf("your login expired %s ago. Run 'vstats login --refresh' or 'vstats login'", formatDuration(-remaining))
+	case remaining < tokenExpiryWarning && !quiet:
+		fmt.Fprintf(os.Stderr, "Warning: your login expires in %s. Run 'vstats login --refresh' to extend it\n", formatDuration(remaining))
+	}
+	return nil
+}
+
+// refreshLogin re-verifies the stored token and extends its expiry
+func refreshLogin() error {
+	if !IsLoggedIn() {
+		return fmt.Errorf("not logged in. Run 'vstats login' first")
+	}
+
+	fmt.Println("Verifying token...")
+	resp, err := NewClient().VerifyToken()
+	if err != nil {
+		return fmt.Errorf("token refresh failed: %w. Run 'vstats login' to log in again", err)
+	}
+	if !resp.Valid {
+		return fmt.Errorf("token is no longer valid. Run 'vstats login' to log in again")
+	}
+
+	cfg.Username = resp.Username
+	cfg.ExpiresAt = time.Now().Add(tokenLifetime).Unix()
+	if err := SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("✓ Login for %s extended until %s\n", resp.Username, time.Unix(cfg.ExpiresAt, 0).Format("2006-01-02 15:04"))
 	return nil
 }
 
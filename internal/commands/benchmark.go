@@ -0,0 +1,250 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// maxProbeDuration bounds how long the on-box CPU sample may run
+const maxProbeDuration = 30 * time.Second
+
+// probeScript samples /proc on a Linux host without changing anything.
+// %d is the CPU sampling interval in seconds. Fields are printed as read and
+// the arithmetic is done by parseProbeOutput: mawk prints large numbers in
+// exponent form, which loses the precision the CPU delta needs.
+const probeScript = `[ -r /proc/stat ] || { echo "unsupported: /proc/stat not readable" >&2; exit 3; }
+cpu() { awk '/^cpu /{print $2, $3, $4, $5, $6, $7, $8, $9}' /proc/stat; }
+a=$(cpu); sleep %d; b=$(cpu)
+echo "cpu $a $b"
+awk '/^MemTotal:/{t=$2} /^MemAvailable:/{a=$2} END{print "mem", t, a}' /proc/meminfo
+df -Pk / | awk 'NR==2{print "disk", $2, $3}'
+echo "load $(cut -d' ' -f1-3 /proc/loadavg)"
+echo "cores $(nproc)"`
+
+// probeCPUFields is the number of /proc/stat cpu fields in each CPU sample:
+// user, nice, system, idle, iowait, irq, softirq, steal
+const probeCPUFields = 8
+
+// ProbeMetrics are the metrics measured directly on the host
+type ProbeMetrics struct {
+	CPUUsage    *float64 `json:"cpu_usage,omitempty"`
+	CPUCores    *int     `json:"cpu_cores,omitempty"`
+	LoadAvg1    *float64 `json:"load_avg_1,omitempty"`
+	LoadAvg5    *float64 `json:"load_avg_5,omitempty"`
+	LoadAvg15   *float64 `json:"load_avg_15,omitempty"`
+	MemoryTotal *int64   `json:"memory_total,omitempty"`
+	MemoryUsed  *int64   `json:"memory_used,omitempty"`
+	DiskTotal   *int64   `json:"disk_total,omitempty"`
+	DiskUsed    *int64   `json:"disk_used,omitempty"`
+}
+
+// BenchmarkResult pairs what the agent reports with the on-box probe
+type BenchmarkResult struct {
+	ServerID string         `json:"server_id" yaml:"server_id"`
+	Name     string         `json:"name" yaml:"name"`
+	Host     string         `json:"host" yaml:"host"`
+	Agent    *ServerMetrics `json:"agent" yaml:"agent"`
+	Probe    *ProbeMetrics  `json:"probe" yaml:"probe"`
+}
+
+// serverBenchmarkCmd compares agent metrics with a direct measurement
+var serverBenchmarkCmd = &cobra.Command{
+	Use:   "benchmark <id>",
+	Short: "Compare agent metrics with a direct on-box measurement",
+	Long: `Connect to a server over SSH, measure CPU, memory, disk, and load
+directly from /proc, and print the result next to what the agent reports.
+
+This helps confirm that the monitoring numbers match reality when a
+server's metrics look suspicious. The probe only reads system files and
+samples CPU for --duration (at most 30s); it doesn't generate load or
+change anything on the host. Linux hosts only.
+
+The server's IP address (or hostname) is used unless --host is given.
+
+Examples:
+  vstats server benchmark web-01
+  vstats server benchmark web-01 --host web-01.internal -u admin
+  vstats server benchmark web-01 --duration 10s`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireLogin(); err != nil {
+			return err
+		}
+
+		duration, _ := cmd.Flags().GetDuration("duration")
+		if duration < time.Second || duration > maxProbeDuration {
+			return fmt.Errorf("--duration must be between 1s and %s", maxProbeDuration)
+		}
+
+		client := NewClient()
+		server, err := findServerByNameOrID(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		host, _ := cmd.Flags().GetString("host")
+		if host == "" && server.IPAddress != nil {
+			host = *server.IPAddress
+		}
+		if host == "" && server.Hostname != nil {
+			host = *server.Hostname
+		}
+		if host == "" {
+			return fmt.Errorf("server '%s' has no IP address or hostname; use --host", server.Name)
+		}
+		user, host := resolveSSHTarget(host)
+
+		if outputFmt == "table" {
+			fmt.Printf("Probing %s@%s for %s...\n", user, host, duration)
+		}
+		var out bytes.Buffer
+		script := fmt.Sprintf(probeScript, int(duration.Round(time.Second)/time.Second))
+		if err := runSSHCommandIO(buildSSHArgs(user, host), script, os.Stdin, &out, os.Stderr); err != nil {
+			return fmt.Errorf("probe failed: %w", err)
+		}
+		probe, err := parseProbeOutput(out.String())
+		if err != nil {
+			return err
+		}
+
+		// Fetch agent metrics right after sampling so both cover the same moment
+		resp, err := client.GetServerMetrics(server.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get metrics: %w", err)
+		}
+
+		result := BenchmarkResult{ServerID: server.ID, Name: server.Name, Host: host, Agent: resp.Metrics, Probe: probe}
+		switch outputFmt {
+		case "json":
+			return OutputJSON(result)
+		case "jsonl":
+			return OutputJSONL(result)
+		case "yaml":
+			return OutputYAML(result)
+		case "csv":
+			return OutputCSV(result)
+		case "template":
+			return OutputTemplate(result)
+		default:
+			printBenchmark(result)
+		}
+		return nil
+	},
+}
+
+// parseProbeOutput parses the key/value lines printed by probeScript
+func parseProbeOutput(output string) (*ProbeMetrics, error) {
+	probe := &ProbeMetrics{}
+	floatPtr := func(v float64) *float64 { return &v }
+	kbPtr := func(kb int64) *int64 { n := kb * 1024; return &n }
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		values := fields[1:]
+		bad := fmt.Errorf("unexpected probe output: %q", scanner.Text())
+
+		// Counters and sizes are integers; only load averages are fractional
+		var ints []int64
+		var floats []float64
+		for _, f := range values {
+			if fields[0] == "load" {
+				n, err := strconv.ParseFloat(f, 64)
+				if err != nil {
+					return nil, bad
+				}
+				floats = append(floats, n)
+				continue
+			}
+			n, err := strconv.ParseInt(f, 10, 64)
+			if err != nil {
+				return nil, bad
+			}
+			ints = append(ints, n)
+		}
+
+		switch {
+		case fields[0] == "cpu" && len(ints) == 2*probeCPUFields:
+			before, after := ints[:probeCPUFields], ints[probeCPUFields:]
+			var total int64
+			for i := range before {
+				total += after[i] - before[i]
+			}
+			idle := after[3] + after[4] - before[3] - before[4] // idle + iowait
+			if total > 0 {
+				probe.CPUUsage = floatPtr((1 - float64(idle)/float64(total)) * 100)
+			}
+		case fields[0] == "mem" && len(ints) == 2:
+			total, available := ints[0], ints[1]
+			probe.MemoryTotal, probe.MemoryUsed = kbPtr(total), kbPtr(total-available)
+		case fields[0] == "disk" && len(ints) == 2:
+			probe.DiskTotal, probe.DiskUsed = kbPtr(ints[0]), kbPtr(ints[1])
+		case fields[0] == "load" && len(floats) == 3:
+			probe.LoadAvg1, probe.LoadAvg5, probe.LoadAvg15 = floatPtr(floats[0]), floatPtr(floats[1]), floatPtr(floats[2])
+		case fields[0] == "cores" && len(ints) == 1:
+			cores := int(ints[0])
+			probe.CPUCores = &cores
+		}
+	}
+	if probe.CPUUsage == nil && probe.MemoryTotal == nil {
+		return nil, fmt.Errorf("probe returned no measurements")
+	}
+	return probe, nil
+}
+
+// printBenchmark renders agent and probe metrics side by side
+func printBenchmark(r BenchmarkResult) {
+	agent := r.Agent
+	if agent == nil {
+		agent = &ServerMetrics{}
+	}
+	p := r.Probe
+
+	percentDiff := func(a, b *float64) string {
+		if a == nil || b == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%+.1f", *a-*b)
+	}
+	usage := func(used, total *int64) *float64 {
+		if pct, ok := usagePercent(used, total); ok {
+			return &pct
+		}
+		return nil
+	}
+	agentMem, probeMem := usage(agent.MemoryUsed, agent.MemoryTotal), usage(p.MemoryUsed, p.MemoryTotal)
+	agentDisk, probeDisk := usage(agent.DiskUsed, agent.DiskTotal), usage(p.DiskUsed, p.DiskTotal)
+
+	fmt.Printf("\nBenchmark for %s (%s)\n\n", r.Name, r.Host)
+	table := NewTable("METRIC", "AGENT", "ON-BOX", "DIFF")
+	table.AddRow("CPU Usage", ptrFloat(agent.CPUUsage), ptrFloat(p.CPUUsage), percentDiff(agent.CPUUsage, p.CPUUsage))
+	table.AddRow("CPU Cores", ptrInt(agent.CPUCores), ptrInt(p.CPUCores), "")
+	table.AddRow("Load Avg 1m", ptrFloatRaw(agent.LoadAvg1), ptrFloatRaw(p.LoadAvg1), percentDiff(agent.LoadAvg1, p.LoadAvg1))
+	table.AddRow("Memory Usage", ptrFloat(agentMem), ptrFloat(probeMem), percentDiff(agentMem, probeMem))
+	table.AddRow("Memory Used", ptrBytes(agent.MemoryUsed), ptrBytes(p.MemoryUsed), "")
+	table.AddRow("Disk Usage", ptrFloat(agentDisk), ptrFloat(probeDisk), percentDiff(agentDisk, probeDisk))
+	table.AddRow("Disk Used", ptrBytes(agent.DiskUsed), ptrBytes(p.DiskUsed), "")
+	table.Render()
+	fmt.Println()
+	fmt.Println("CPU on-box is averaged over the probe window; the agent reports its latest sample.")
+}
+
+func init() {
+	serverCmd.AddCommand(serverBenchmarkCmd)
+
+	serverBenchmarkCmd.Flags().String("host", "", "SSH host to probe (default: the server's IP or hostname)")
+	serverBenchmarkCmd.Flags().StringVarP(&sshUser, "user", "u", "", "SSH username (default: root)")
+	serverBenchmarkCmd.Flags().IntVarP(&sshPort, "port", "p", 0, "SSH port (uses ssh config default)")
+	serverBenchmarkCmd.Flags().StringVarP(&sshKey, "key", "i", "", "SSH private key path")
+	serverBenchmarkCmd.Flags().Duration("duration", 5*time.Second, "how long to sample CPU on the host")
+}
@@ -0,0 +1,31 @@
+package commands
+
+import "testing"
+
+func TestParseProbeOutputLargeCounters(t *testing.T) {
+	// Jiffy sums and sizes above 2^31, which mawk would print as 2.14748e+09
+	output := `cpu 2147483000 0 1000 3000000000 0 0 0 0 2147483600 0 1400 3000000100 0 0 0 0
+mem 3221225472 1073741824
+disk 4294967296 2147483649
+load 0.50 1.25 2.00
+cores 8
+`
+	probe, err := parseProbeOutput(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1100 jiffies elapsed, 100 of them idle
+	if probe.CPUUsage == nil || *probe.CPUUsage < 90.9 || *probe.CPUUsage > 91 {
+		t.Errorf("CPUUsage = %v, want about 90.9", probe.CPUUsage)
+	}
+	if *probe.MemoryTotal != 3221225472*1024 || *probe.MemoryUsed != (3221225472-1073741824)*1024 {
+		t.Errorf("memory = %d/%d", *probe.MemoryUsed, *probe.MemoryTotal)
+	}
+	if *probe.DiskTotal != 4294967296*1024 || *probe.DiskUsed != 2147483649*1024 {
+		t.Errorf("disk = %d/%d", *probe.DiskUsed, *probe.DiskTotal)
+	}
+	if *probe.LoadAvg5 != 1.25 || *probe.CPUCores != 8 {
+		t.Errorf("load5 = %v, cores = %v", *probe.LoadAvg5, *probe.CPUCores)
+	}
+}
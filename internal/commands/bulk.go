@@ -0,0 +1,425 @@
+package commands
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// maxBulkDelete is the most servers a single delete may remove without
+// --i-really-mean-it, so a mistyped pattern can't wipe out the fleet
+const maxBulkDelete = 50
+
+// bulkResult is the outcome of one operation in a bulk run
+type bulkResult struct {
+	Server Server
+	Err    error
+}
+
+// isGlobPattern reports whether s contains glob metacharacters
+func isGlobPattern(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+// resolveServerTargets resolves IDs, names, and name glob patterns to a
+// de-duplicated list of servers
+func resolveServerTargets(client *Client, args []string) ([]Server, error) {
+	var all []Server
+	var servers []Server
+	seen := make(map[string]bool)
+
+	add := func(s Server) {
+		if !seen[s.ID] {
+			seen[s.ID] = true
+			servers = append(servers, s)
+		}
+	}
+
+	for _, arg := range args {
+		if !isGlobPattern(arg) {
+			server, err := findServerByNameOrID(client, arg)
+			if err != nil {
+				return nil, err
+			}
+			add(*server)
+			continue
+		}
+
+		if all == nil {
+			var err error
+			if all, err = client.ListServers(); err != nil {
+				return nil, fmt.Errorf("failed to list servers: %w", err)
+			}
+		}
+
+		matched := false
+		for _, s := range all {
+			ok, err := path.Match(arg, s.Name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", arg, err)
+			}
+			if ok {
+				matched = true
+				add(s)
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no servers match pattern: %s", arg)
+		}
+	}
+	return servers, nil
+}
+
+// runBulkDelete confirms and deletes several servers with bounded
+// concurrency. With typeCount the user confirms by typing the number of
+// servers instead of answering yes.
+func runBulkDelete(cmd *cobra.Command, client *Client, servers []Server, typeCount bool) error {
+	reallyMeanIt, _ := cmd.Flags().GetBool("i-really-mean-it")
+	if len(servers) > maxBulkDelete && !reallyMeanIt {
+		return fmt.Errorf("refusing to delete %d servers (safety cap is %d). Pass --i-really-mean-it to proceed",
+			len(servers), maxBulkDelete)
+	}
+
+	if dryRun {
+		for _, s := range servers {
+			printDryRun("DELETE", "/api/servers/"+s.ID, nil, "would delete server '%s'", s.Name)
+		}
+		return nil
+	}
+
+	printDeleteTargets(servers)
+
+	force, _ := cmd.Flags().GetBool("force")
+	if !force {
+		var ok bool
+		var err error
+		if typeCount {
+			ok, err = confirmCount(len(servers))
+		} else {
+			ok, err = confirm(fmt.Sprintf("Are you sure you want to delete these %d servers?", len(servers)))
+		}
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	results := deleteServers(client, servers)
+	return renderBulkResults(results, "deleted", "deletions")
+}
+
+// printDeleteTargets lists the servers about to be deleted
+func printDeleteTargets(servers []Server) {
+	fmt.Printf("The following %d servers will be deleted:\n\n", len(servers))
+	table := NewTable("NAME", "ID", "STATUS")
+	for _, s := range servers {
+		table.AddRow(s.Name, s.ID, formatStatus(s.Status))
+	}
+	table.Render()
+	fmt.Println()
+}
+
+// serverBulkDeleteCmd deletes every server matching a selector
+var serverBulkDeleteCmd = &cobra.Command{
+	Use:   "bulk-delete [id]...",
+	Short: "Delete all servers matching a selector",
+	Long: `Delete every server matching --status, --filter, --tag, or an explicit
+list of IDs, e.g. to clean up after decommissioning a batch of machines.
+
+When IDs are given together with filters, only the listed servers that
+also match the filters are deleted. The matched servers are shown first,
+and the deletion must be confirmed by typing their count (skip with
+--force). A failed deletion doesn't stop the others; a summary
+of how many succeeded and failed is printed at the end.
+
+Examples:
+  vstats server bulk-delete --status offline
+  vstats server bulk-delete --filter staging --status offline
+  vstats server bulk-delete 3f2a9c1e 8b7d6e5f --force`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireLogin(); err != nil {
+			return err
+		}
+
+		filter, err := serverFilterFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 && !filter.active() {
+			return fmt.Errorf("no servers selected. Use --status, --filter, --tag, or list server IDs")
+		}
+
+		client := NewClient()
+		var servers []Server
+		if len(args) > 0 {
+			if servers, err = resolveServerTargets(client, args); err != nil {
+				return err
+			}
+		} else if servers, err = client.ListServers(); err != nil {
+			return fmt.Errorf("failed to list servers: %w", err)
+		}
+		servers = filter.apply(servers)
+		if len(servers) == 0 {
+			fmt.Println("No servers match the given selector")
+			return nil
+		}
+		return runBulkDelete(cmd, client, servers, true)
+	},
+}
+
+// deleteServers deletes servers in parallel, reporting progress as it goes
+func deleteServers(client *Client, servers []Server) []bulkResult {
+	results := make([]bulkResult, len(servers))
+	progress := newProgressBar(len(servers))
+	forEachParallel(len(servers), concurrency, func(i int) {
+		results[i] = bulkResult{Server: servers[i], Err: client.DeleteServer(servers[i].ID)}
+		progress.Increment()
+	})
+	progress.Finish()
+	invalidateServerCache()
+	return results
+}
+
+// renderBulkResults prints a per-server summary table and returns an error
+// if any operation failed
+func renderBulkResults(results []bulkResult, verb, noun string) error {
+	failed := 0
+	table := NewTable("NAME", "ID", "RESULT")
+	for _, r := range results {
+		result := color(themeColor(RoleOnline), "✓ "+verb)
+		if r.Err != nil {
+			failed++
+			result = color(themeColor(RoleCritical), "✗ "+r.Err.Error())
+		}
+		table.AddRow(r.Server.Name, r.Server.ID, result)
+	}
+	table.Render()
+
+	fmt.Println()
+	fmt.Printf("%d succeeded, %d failed\n", len(results)-failed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d %s failed", failed, len(results), noun)
+	}
+	return nil
+}
+
+// bulkUpdateRow is one change in a bulk update file
+type bulkUpdateRow struct {
+	Server string            `yaml:"server"`
+	Name   string            `yaml:"name,omitempty"`
+	Tags   map[string]string `yaml:"tags,omitempty"`
+	Notes  string            `yaml:"notes,omitempty"`
+}
+
+// readBulkUpdateFile reads changes from a CSV or YAML file. CSV files need a
+// header row with a server column and any of name, tags, and notes; tags
+// are written as key=value pairs separated by semicolons.
+func readBulkUpdateFile(path string) ([]bulkUpdateRow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if isYAMLFile(path) {
+		var rows []bulkUpdateRow
+		if err := yaml.Unmarshal(data, &rows); err != nil {
+			return nil, fmt.Errorf("invalid YAML in %s: %w", path, err)
+		}
+		return rows, nil
+	}
+
+	reader := csv.NewReader(strings.NewReader(string(data)))
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header from %s: %w", path, err)
+	}
+
+	columns := make(map[string]int)
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	if _, ok := columns["server"]; !ok {
+		return nil, fmt.Errorf("%s must have a 'server' column", path)
+	}
+	for name := range columns {
+		switch name {
+		case "server", "name", "tags", "notes":
+		default:
+			return nil, fmt.Errorf("unknown column %q in %s (use server, name, tags, notes)", name, path)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []bulkUpdateRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", path, line, err)
+		}
+
+		row := bulkUpdateRow{
+			Server: field(record, "server"),
+			Name:   field(record, "name"),
+			Notes:  field(record, "notes"),
+		}
+		if tags := field(record, "tags"); tags != "" {
+			if row.Tags, err = parseTagList(tags, ";"); err != nil {
+				return nil, fmt.Errorf("%s line %d: %w", path, line, err)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseTagList parses key=value pairs separated by sep
+func parseTagList(s, sep string) (map[string]string, error) {
+	tags := make(map[string]string)
+	for _, pair := range strings.Split(s, sep) {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid tag %q (expected key=value)", pair)
+		}
+		tags[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return tags, nil
+}
+
+// runBulkUpdate validates a bulk update file and applies it with bounded concurrency
+func runBulkUpdate(client *Client, path string) error {
+	rows, err := readBulkUpdateFile(path)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("no changes found in %s", path)
+	}
+
+	all, err := client.ListServers()
+	if err != nil {
+		return fmt.Errorf("failed to list servers: %w", err)
+	}
+
+	// Validate every row before applying anything
+	servers := make([]Server, len(rows))
+	updates := make([]*ServerUpdate, len(rows))
+	var problems []string
+	for i, row := range rows {
+		server, matchErr := matchServer(all, row.Server)
+		switch {
+		case row.Server == "":
+			problems = append(problems, fmt.Sprintf("entry %d: missing server", i+1))
+			continue
+		case matchErr != nil:
+			problems = append(problems, fmt.Sprintf("entry %d: %v", i+1, matchErr))
+			continue
+		case row.Tags != nil && len(row.Tags) == 0:
+			// An empty map would be dropped from the request, so it can't
+			// clear tags; say so instead of reporting a no-op as updated
+			problems = append(problems, fmt.Sprintf("entry %d: empty tags for %s (clearing tags isn't supported; list at least one key=value)", i+1, row.Server))
+			continue
+		case row.Name == "" && row.Tags == nil && row.Notes == "":
+			problems = append(problems, fmt.Sprintf("entry %d: no changes for %s", i+1, row.Server))
+			continue
+		}
+		if row.Name != "" {
+			if err := validateServerName(row.Name); err != nil {
+				problems = append(problems, fmt.Sprintf("entry %d: %v", i+1, err))
+				continue
+			}
+		}
+
+		update := &ServerUpdate{Tags: row.Tags}
+		if row.Name != "" {
+			update.Name = &rows[i].Name
+		}
+		if row.Notes != "" {
+			update.Notes = &rows[i].Notes
+		}
+		servers[i] = server
+		updates[i] = update
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid update file %s:\n  %s", path, strings.Join(problems, "\n  "))
+	}
+
+	if dryRun {
+		for i, s := range servers {
+			printDryRun("PUT", "/api/servers/"+s.ID, updates[i], "would update server '%s'", s.Name)
+		}
+		return nil
+	}
+
+	results := make([]bulkResult, len(rows))
+	progress := newProgressBar(len(rows))
+	forEachParallel(len(rows), concurrency, func(i int) {
+		_, err := client.UpdateServerFields(servers[i].ID, updates[i])
+		results[i] = bulkResult{Server: servers[i], Err: err}
+		progress.Increment()
+	})
+	progress.Finish()
+	invalidateServerCache()
+
+	return renderBulkResults(results, "updated", "updates")
+}
+
+// matchServer finds a server in a list by ID or exact name. A name shared
+// by several servers is an error rather than a guess, since the update
+// would otherwise land on whichever the API listed first.
+func matchServer(servers []Server, nameOrID string) (Server, error) {
+	for _, s := range servers {
+		if s.ID == nameOrID {
+			return s, nil
+		}
+	}
+	var matches []Server
+	for _, s := range servers {
+		if s.Name == nameOrID {
+			matches = append(matches, s)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Server{}, fmt.Errorf("server not found: %s", nameOrID)
+	case 1:
+		return matches[0], nil
+	}
+	ids := make([]string, len(matches))
+	for i, s := range matches {
+		ids[i] = s.ID
+	}
+	return Server{}, fmt.Errorf("%d servers are named %s; use an ID instead (%s)", len(matches), nameOrID, strings.Join(ids, ", "))
+}
+
+func init() {
+	serverCmd.AddCommand(serverBulkDeleteCmd)
+
+	serverBulkDeleteCmd.Flags().String("status", "", "select servers with this status (online, offline, pending, paused)")
+	serverBulkDeleteCmd.Flags().String("filter", "", "select servers whose name, hostname, or IP contains this text")
+	serverBulkDeleteCmd.Flags().StringArray("tag", nil, "select servers with this tag, e.g. env=staging (repeatable)")
+	serverBulkDeleteCmd.Flags().BoolP("force", "f", false, "delete without confirmation (same as --yes)")
+	serverBulkDeleteCmd.Flags().Bool("i-really-mean-it", false, "allow deleting more than 50 servers at once")
+}
@@ -0,0 +1,24 @@
+package commands
+
+import "testing"
+
+func TestMatchServer(t *testing.T) {
+	servers := []Server{
+		{ID: "a", Name: "web"},
+		{ID: "b", Name: "db"},
+		{ID: "c", Name: "db"},
+	}
+
+	if s, err := matchServer(servers, "web"); err != nil || s.ID != "a" {
+		t.Errorf("matchServer(web) = %q, %v, want a", s.ID, err)
+	}
+	if s, err := matchServer(servers, "c"); err != nil || s.ID != "c" {
+		t.Errorf("matchServer(c) = %q, %v, want c", s.ID, err)
+	}
+	if _, err := matchServer(servers, "db"); err == nil {
+		t.Error("matchServer(db) matched one of two servers with that name, want an error")
+	}
+	if _, err := matchServer(servers, "missing"); err == nil {
+		t.Error("matchServer(missing) succeeded, want an error")
+	}
+}
@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// serverListCache is the on-disk cache of the server list
+type serverListCache struct {
+	CloudURL  string    `json:"cloud_url"`
+	Username  string    `json:"username"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Servers   []Server  `json:"servers"`
+}
+
+// cachedServerList is a server list served from the cache or the API
+type cachedServerList struct {
+	Servers []Server
+	Age     time.Duration
+	Cached  bool
+	Stale   bool
+}
+
+// getServerCachePath returns the server list cache file path
+func getServerCachePath() (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache", "servers.json"), nil
+}
+
+// getCacheDurations returns the configured cache TTL and stale-while-revalidate window
+func getCacheDurations() (time.Duration, time.Duration) {
+	var ttl, swr time.Duration
+	if cfg.CacheTTL != "" {
+		ttl, _ = time.ParseDuration(cfg.CacheTTL)
+	}
+	if cfg.CacheSWR != "" {
+		swr, _ = time.ParseDuration(cfg.CacheSWR)
+	}
+	return ttl, swr
+}
+
+// loadServerCache reads the cache, ignoring entries for another account
+func loadServerCache() (*serverListCache, error) {
+	path, err := getServerCachePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cache serverListCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	if cache.CloudURL != currentCloudURL() || cache.Username != cfg.Username {
+		return nil, fmt.Errorf("cache belongs to a different account")
+	}
+	return &cache, nil
+}
+
+// saveServerCache writes the server list to the cache
+func saveServerCache(servers []Server) error {
+	path, err := getServerCachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(serverListCache{
+		CloudURL:  currentCloudURL(),
+		Username:  cfg.Username,
+		FetchedAt: time.Now(),
+		Servers:   servers,
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// invalidateServerCache drops the cached server list after a mutation
+func invalidateServerCache() {
+	if path, err := getServerCachePath(); err == nil {
+		_ = os.Remove(path)
+	}
+}
+
+// listServersCached returns the server list, serving it from the cache when
+// it is younger than maxAge. Within the stale-while-revalidate window after
+// that, the stale list is returned immediately and refreshed in the
+// background. The returned wait func blocks until any background refresh
+// finishes and must be called before the process exits.
+//
+// fields projects servers fetched on a cache miss (see ListServers); such
+// partial lists are never cached, while refreshes always fetch full ones.
+func listServersCached(client *Client, maxAge, swr time.Duration, fields []string) (*cachedServerList, func(), error) {
+	noWait := func() {}
+
+	if maxAge <= 0 {
+		servers, err := client.ListServers(fields...)
+		if err != nil {
+			return nil, noWait, err
+		}
+		return &cachedServerList{Servers: servers}, noWait, nil
+	}
+
+	if cache, err := loadServerCache(); err == nil {
+		age := time.Since(cache.FetchedAt)
+		if age <= maxAge {
+			return &cachedServerList{Servers: cache.Servers, Age: age, Cached: true}, noWait, nil
+		}
+		if age <= maxAge+swr {
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				if servers, err := client.ListServers(); err == nil {
+					_ = saveServerCache(servers)
+				}
+			}()
+			list := &cachedServerList{Servers: cache.Servers, Age: age, Cached: true, Stale: true}
+			return list, func() { <-done }, nil
+		}
+	}
+
+	servers, err := client.ListServers(fields...)
+	if err != nil {
+		return nil, noWait, err
+	}
+	if len(fields) == 0 {
+		_ = saveServerCache(servers)
+	}
+	return &cachedServerList{Servers: servers}, noWait, nil
+}
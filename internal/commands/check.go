@@ -0,0 +1,206 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// StateUnknown is the check state when metrics can't be evaluated
+const StateUnknown = "unknown"
+
+// CheckItem is one evaluated metric of a server check
+type CheckItem struct {
+	Metric   string  `json:"metric" yaml:"metric"`
+	Value    float64 `json:"value" yaml:"value"`
+	Warning  float64 `json:"warning" yaml:"warning"`
+	Critical float64 `json:"critical" yaml:"critical"`
+	State    string  `json:"state" yaml:"state"`
+}
+
+// CheckResult is the outcome of a server check
+type CheckResult struct {
+	ServerID   string      `json:"server_id" yaml:"server_id"`
+	Name       string      `json:"name" yaml:"name"`
+	State      string      `json:"state" yaml:"state"`
+	Message    string      `json:"message" yaml:"message"`
+	LastSeenAt *time.Time  `json:"last_seen_at,omitempty" yaml:"last_seen_at,omitempty"`
+	Checks     []CheckItem `json:"checks" yaml:"checks"`
+}
+
+// checkStateCodes maps check states to Nagios exit codes
+var checkStateCodes = map[string]int{
+	StateOK:       ExitOK,
+	StateWarning:  ExitWarning,
+	StateCritical: ExitCritical,
+	StateUnknown:  ExitUnknown,
+}
+
+var serverCheckCmd = &cobra.Command{
+	Use:   "check <id>",
+	Short: "Check a server against thresholds (Nagios-style)",
+	Long: `Check a server's current metrics against thresholds and print a
+single status line, exiting with the Nagios plugin codes:
+  0  OK        all metrics below their warning level
+  1  WARNING   a metric at or above its warning level
+  2  CRITICAL  a metric at or above its critical level, or the server
+               hasn't been seen for --offline-after
+  3  UNKNOWN   the server or its metrics couldn't be fetched
+
+--cpu, --mem, and --disk set the critical level in percent; they default
+to the configured thresholds (see 'vstats config set thresholds.*'). The
+warning level is the configured warning threshold, capped at the critical
+level. Performance data follows the '|' for graphing.
+
+Examples:
+  vstats server check web-01
+  vstats server check web-01 --cpu 95 --disk 85 --offline-after 10m
+  vstats server check web-01 -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireLogin(); err != nil {
+			return &ExitError{Code: ExitUnknown, Err: err}
+		}
+
+		offlineAfter, _ := cmd.Flags().GetDuration("offline-after")
+		critical := make(map[string]float64)
+		for flag, metric := range map[string]string{"cpu": MetricCPU, "mem": MetricMemory, "disk": MetricDisk} {
+			value, _ := cmd.Flags().GetFloat64(flag)
+			if value < 0 || value > 100 {
+				return &ExitError{Code: ExitUnknown, Err: fmt.Errorf("--%s must be a percentage between 0 and 100", flag)}
+			}
+			critical[metric] = value
+		}
+
+		client := NewClient()
+		server, err := findServerByNameOrID(client, args[0])
+		if err != nil {
+			return &ExitError{Code: ExitUnknown, Err: err}
+		}
+		var metrics *ServerMetrics
+		if resp, err := client.GetServerMetrics(server.ID); err == nil {
+			metrics = resp.Metrics
+		}
+
+		result := checkServer(server, metrics, critical, offlineAfter)
+		switch outputFmt {
+		case "json":
+			err = OutputJSON(result)
+		case "jsonl":
+			err = OutputJSONL(result)
+		case "yaml":
+			err = OutputYAML(result)
+		case "csv":
+			err = OutputCSV(result)
+		case "template":
+			err = OutputTemplate(result)
+		default:
+			fmt.Println(result.Message)
+		}
+		if err != nil {
+			return &ExitError{Code: ExitUnknown, Err: err}
+		}
+
+		if code := checkStateCodes[result.State]; code != ExitOK {
+			return &ExitError{Code: code, Err: errors.New(result.Message), Silent: true}
+		}
+		return nil
+	},
+}
+
+// checkServer evaluates a server's metrics and last-seen time. critical
+// overrides the configured critical level for a metric when non-zero.
+func checkServer(server *Server, m *ServerMetrics, critical map[string]float64, offlineAfter time.Duration) CheckResult {
+	result := CheckResult{ServerID: server.ID, Name: server.Name, LastSeenAt: server.LastSeenAt, State: StateOK}
+
+	var problems []string
+	if offlineAfter > 0 && (server.LastSeenAt == nil || time.Since(*server.LastSeenAt) > offlineAfter) {
+		result.State = StateCritical
+		if server.LastSeenAt == nil {
+			problems = append(problems, "never seen")
+		} else {
+			problems = append(problems, "last seen "+formatTimeAgo(server.LastSeenAt))
+		}
+	}
+
+	if m == nil {
+		if result.State == StateOK {
+			result.State = StateUnknown
+		}
+		problems = append(problems, "no metrics")
+	} else {
+		add := func(metric string, value float64, ok bool) {
+			if !ok {
+				return
+			}
+			levels := thresholdsFor(metric)
+			if c := critical[metric]; c > 0 {
+				levels.Critical = c
+			}
+			if levels.Warning > levels.Critical {
+				levels.Warning = levels.Critical
+			}
+			item := CheckItem{Metric: metric, Value: value, Warning: levels.Warning, Critical: levels.Critical, State: StateOK}
+			switch {
+			case value >= levels.Critical:
+				item.State = StateCritical
+			case value >= levels.Warning:
+				item.State = StateWarning
+			}
+			result.Checks = append(result.Checks, item)
+			result.State = worseCheckState(result.State, item.State)
+		}
+
+		var cpu float64
+		if m.CPUUsage != nil {
+			cpu = *m.CPUUsage
+		}
+		add(MetricCPU, cpu, m.CPUUsage != nil)
+		mem, ok := usagePercent(m.MemoryUsed, m.MemoryTotal)
+		add(MetricMemory, mem, ok)
+		disk, ok := usagePercent(m.DiskUsed, m.DiskTotal)
+		add(MetricDisk, disk, ok)
+	}
+
+	var values, perfdata []string
+	for _, c := range result.Checks {
+		value := fmt.Sprintf("%s %.1f%%", c.Metric, c.Value)
+		switch c.State {
+		case StateCritical:
+			value += fmt.Sprintf(" (critical >= %g%%)", c.Critical)
+		case StateWarning:
+			value += fmt.Sprintf(" (warning >= %g%%)", c.Warning)
+		}
+		values = append(values, value)
+		perfdata = append(perfdata, fmt.Sprintf("%s=%.1f%%;%g;%g;0;100", c.Metric, c.Value, c.Warning, c.Critical))
+	}
+	values = append(values, problems...)
+
+	result.Message = fmt.Sprintf("%s - %s: %s", strings.ToUpper(result.State), server.Name, strings.Join(values, ", "))
+	if len(perfdata) > 0 {
+		result.Message += " | " + strings.Join(perfdata, " ")
+	}
+	return result
+}
+
+// worseCheckState returns the more severe of two check states. Critical
+// outranks unknown, which outranks warning.
+func worseCheckState(a, b string) string {
+	rank := map[string]int{StateOK: 0, StateWarning: 1, StateUnknown: 2, StateCritical: 3}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
+
+func init() {
+	serverCmd.AddCommand(serverCheckCmd)
+
+	serverCheckCmd.Flags().Float64("cpu", 0, "critical CPU usage in percent (default: configured threshold)")
+	serverCheckCmd.Flags().Float64("mem", 0, "critical memory usage in percent (default: configured threshold)")
+	serverCheckCmd.Flags().Float64("disk", 0, "critical disk usage in percent (default: configured threshold)")
+	serverCheckCmd.Flags().Duration("offline-after", 5*time.Minute, "critical when the server hasn't been seen for this long (0 to disable)")
+}
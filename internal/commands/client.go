@@ -2,29 +2,119 @@ package commands
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// unixSocketScheme is the cloud_url prefix used to reach a co-located
+// service over a Unix domain socket, e.g. unix:///var/run/vstats.sock
+const unixSocketScheme = "unix://"
+
 // Client represents the vStats Cloud API client
 type Client struct {
 	BaseURL    string
 	Token      string
 	HTTPClient *http.Client
+
+	// Context cancels in-flight requests, e.g. on Ctrl-C
+	Context context.Context
+
+	// DryRun refuses every request that could change something, as a
+	// safety net for commands that forget to check --dry-run
+	DryRun bool
+
+	// Debug is the --debug level; requests are logged to Logger when > 0
+	Debug  int
+	Logger io.Writer
 }
 
 // NewClient creates a new API client
 func NewClient() *Client {
-	return &Client{
-		BaseURL: cfg.CloudURL,
-		Token:   cfg.Token,
+	return newClientFor(currentCloudURL(), currentToken())
+}
+
+// DefaultRequestTimeout is the HTTP timeout used when none is configured
+const DefaultRequestTimeout = 30 * time.Second
+
+// dialTimeout bounds connection setup, so unreachable hosts fail fast
+// even when the overall request timeout is long or disabled
+const dialTimeout = 10 * time.Second
+
+// newClientFor creates an API client for a specific cloud URL and token
+func newClientFor(cloudURL, token string) *Client {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	if clientTLSConfig != nil {
+		transport.TLSClientConfig = clientTLSConfig.Clone()
+	}
+
+	client := &Client{
+		BaseURL: cloudURL,
+		Token:   token,
 		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   effectiveRequestTimeout(),
+			Transport: transport,
 		},
+		Context: commandContext(),
+		DryRun:  dryRun,
+		Debug:   debugLevel,
+		Logger:  os.Stderr,
+	}
+
+	// Route requests through a Unix socket when the URL uses the unix:// scheme
+	if socketPath, ok := parseUnixSocketURL(cloudURL); ok {
+		client.BaseURL = "http://unix"
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socketPath)
+		}
+	}
+
+	return client
+}
+
+// effectiveRequestTimeout resolves the HTTP timeout: --request-timeout,
+// then request_timeout_seconds, then the default. Zero means no timeout.
+func effectiveRequestTimeout() time.Duration {
+	if requestTimeoutSet {
+		return requestTimeout
+	}
+	if cfg.RequestTimeoutSeconds != nil {
+		return time.Duration(*cfg.RequestTimeoutSeconds) * time.Second
+	}
+	return DefaultRequestTimeout
+}
+
+// parseUnixSocketURL extracts the socket path from a unix:// URL
+func parseUnixSocketURL(rawURL string) (string, bool) {
+	if !strings.HasPrefix(rawURL, unixSocketScheme) {
+		return "", false
 	}
+	socketPath := strings.TrimPrefix(rawURL, unixSocketScheme)
+	if socketPath == "" {
+		return "", false
+	}
+	return socketPath, true
+}
+
+// joinURL joins a base URL and a request path with exactly one slash
+// between them, so a cloud_url ending in / still yields valid URLs. A path
+// that is empty or only a query string is appended as is.
+func joinURL(base, path string) string {
+	base = strings.TrimRight(base, "/")
+	if path == "" || strings.HasPrefix(path, "?") {
+		return base + path
+	}
+	return base + "/" + strings.TrimLeft(path, "/")
 }
 
 // APIError represents an API error response
@@ -33,20 +123,28 @@ type APIError struct {
 	Message string `json:"message,omitempty"`
 }
 
-// Do performs an HTTP request
-func (c *Client) Do(method, path string, body interface{}, result interface{}) error {
+// context returns the client's context, defaulting to Background
+func (c *Client) context() context.Context {
+	if c.Context != nil {
+		return c.Context
+	}
+	return context.Background()
+}
+
+// newRequest builds an authenticated API request with a JSON body
+func (c *Client) newRequest(method, path string, body interface{}) (*http.Request, error) {
 	var bodyReader io.Reader
 	if body != nil {
 		data, err := json.Marshal(body)
 		if err != nil {
-			return fmt.Errorf("failed to marshal request: %w", err)
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
 		}
 		bodyReader = bytes.NewReader(data)
 	}
 
-	req, err := http.NewRequest(method, c.BaseURL+path, bodyReader)
+	req, err := http.NewRequestWithContext(c.context(), method, joinURL(c.BaseURL, path), bodyReader)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -56,9 +154,51 @@ func (c *Client) Do(method, path string, body interface{}, result interface{}) e
 	if c.Token != "" {
 		req.Header.Set("Authorization", "Bearer "+c.Token)
 	}
+	return req, nil
+}
+
+// ResponseError is returned for an HTTP error response. Code and Message
+// are the API's error and message fields, when the body carried them.
+type ResponseError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Body       string
+}
+
+func (e *ResponseError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("API error: %s", e.Code)
+	}
+	return fmt.Sprintf("request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// responseError converts an error response body into an error
+func responseError(status int, body []byte) error {
+	respErr := &ResponseError{StatusCode: status, Body: string(body)}
+	var apiErr APIError
+	if err := json.Unmarshal(body, &apiErr); err == nil {
+		respErr.Code, respErr.Message = apiErr.Error, apiErr.Message
+	}
+	return respErr
+}
+
+// Do performs an HTTP request
+func (c *Client) Do(method, path string, body interface{}, result interface{}) error {
+	if c.DryRun && method != http.MethodGet && method != http.MethodHead {
+		return fmt.Errorf("refusing to send %s %s with --dry-run", method, path)
+	}
+
+	req, err := c.newRequest(method, path, body)
+	if err != nil {
+		return err
+	}
 
+	c.debugRequest(req)
+	start := time.Now()
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
+		c.debugResponse(req, nil, err, start, nil)
 		return fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
@@ -67,13 +207,10 @@ func (c *Client) Do(method, path string, body interface{}, result interface{}) e
 	if err != nil {
 		return fmt.Errorf("failed to read response: %w", err)
 	}
+	c.debugResponse(req, resp, nil, start, respBody)
 
 	if resp.StatusCode >= 400 {
-		var apiErr APIError
-		if err := json.Unmarshal(respBody, &apiErr); err == nil && apiErr.Error != "" {
-			return fmt.Errorf("API error: %s", apiErr.Error)
-		}
-		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(respBody))
+		return responseError(resp.StatusCode, respBody)
 	}
 
 	if result != nil {
@@ -85,66 +222,150 @@ func (c *Client) Do(method, path string, body interface{}, result interface{}) e
 	return nil
 }
 
+// DoStream performs a GET request and hands the successful response body
+// to handle without buffering it
+func (c *Client) DoStream(path string, handle func(body io.Reader) error) error {
+	req, err := c.newRequest("GET", path, nil)
+	if err != nil {
+		return err
+	}
+
+	c.debugRequest(req)
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		c.debugResponse(req, nil, err, start, nil)
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		c.debugResponse(req, resp, nil, start, respBody)
+		return responseError(resp.StatusCode, respBody)
+	}
+	// Streamed bodies are handed on unread, so only the status is logged
+	c.debugResponse(req, resp, nil, start, nil)
+	return handle(resp.Body)
+}
+
+// Probe checks that the API is reachable. Any HTTP response counts as
+// reachable, since unauthenticated endpoints vary between deployments.
+func (c *Client) Probe() error {
+	req, err := http.NewRequestWithContext(c.context(), "GET", joinURL(c.BaseURL, "/api/health"), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "vstats-cli/"+version)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // ============================================================================
 // API Response Types
 // ============================================================================
 
+// The yaml tags pin the keys -o yaml has always printed for these types
+// (yaml.v3's default, the lowercased field name), which is why they don't
+// follow the snake_case JSON keys.
+
 // User represents a user
 type User struct {
-	ID          string  `json:"id"`
-	Username    string  `json:"username"`
-	Email       *string `json:"email,omitempty"`
-	AvatarURL   *string `json:"avatar_url,omitempty"`
-	Plan        string  `json:"plan"`
-	ServerLimit int     `json:"server_limit"`
-	Status      string  `json:"status"`
+	ID          string  `json:"id" yaml:"id"`
+	Username    string  `json:"username" yaml:"username"`
+	Email       *string `json:"email,omitempty" yaml:"email"`
+	AvatarURL   *string `json:"avatar_url,omitempty" yaml:"avatarurl"`
+	Plan        string  `json:"plan" yaml:"plan"`
+	ServerLimit int     `json:"server_limit" yaml:"serverlimit"`
+	Status      string  `json:"status" yaml:"status"`
 }
 
 // Server represents a server
 type Server struct {
-	ID           string         `json:"id"`
-	Name         string         `json:"name"`
-	Hostname     *string        `json:"hostname,omitempty"`
-	IPAddress    *string        `json:"ip_address,omitempty"`
-	AgentKey     string         `json:"agent_key"`
-	AgentVersion *string        `json:"agent_version,omitempty"`
-	OSType       *string        `json:"os_type,omitempty"`
-	OSVersion    *string        `json:"os_version,omitempty"`
-	Status       string         `json:"status"`
-	LastSeenAt   *time.Time     `json:"last_seen_at,omitempty"`
-	CreatedAt    time.Time      `json:"created_at"`
-	Metrics      *ServerMetrics `json:"metrics,omitempty"`
-}
-
-// ServerMetrics represents server metrics
+	ID           string            `json:"id" yaml:"id"`
+	Name         string            `json:"name" yaml:"name"`
+	Hostname     *string           `json:"hostname,omitempty" yaml:"hostname"`
+	IPAddress    *string           `json:"ip_address,omitempty" yaml:"ipaddress"`
+	AgentKey     string            `json:"agent_key" yaml:"agentkey"`
+	AgentVersion *string           `json:"agent_version,omitempty" yaml:"agentversion"`
+	OSType       *string           `json:"os_type,omitempty" yaml:"ostype"`
+	OSVersion    *string           `json:"os_version,omitempty" yaml:"osversion"`
+	Status       string            `json:"status" yaml:"status"`
+	LastSeenAt   *time.Time        `json:"last_seen_at,omitempty" yaml:"lastseenat"`
+	CreatedAt    time.Time         `json:"created_at" yaml:"createdat"`
+	Metrics      *ServerMetrics    `json:"metrics,omitempty" yaml:"metrics"`
+	Tags         map[string]string `json:"tags,omitempty" yaml:"tags"`
+}
+
+// ServerMetrics represents server metrics. NetRxBytes and NetTxBytes are
+// network throughput in bytes per second.
 type ServerMetrics struct {
-	CPUUsage     *float64 `json:"cpu_usage,omitempty"`
-	CPUCores     *int     `json:"cpu_cores,omitempty"`
-	LoadAvg1     *float64 `json:"load_avg_1,omitempty"`
-	LoadAvg5     *float64 `json:"load_avg_5,omitempty"`
-	LoadAvg15    *float64 `json:"load_avg_15,omitempty"`
-	MemoryTotal  *int64   `json:"memory_total,omitempty"`
-	MemoryUsed   *int64   `json:"memory_used,omitempty"`
-	MemoryFree   *int64   `json:"memory_free,omitempty"`
-	DiskTotal    *int64   `json:"disk_total,omitempty"`
-	DiskUsed     *int64   `json:"disk_used,omitempty"`
-	DiskFree     *int64   `json:"disk_free,omitempty"`
-	ProcessCount *int     `json:"process_count,omitempty"`
+	CPUUsage     *float64    `json:"cpu_usage,omitempty" yaml:"cpuusage"`
+	CPUCores     *int        `json:"cpu_cores,omitempty" yaml:"cpucores"`
+	LoadAvg1     *float64    `json:"load_avg_1,omitempty" yaml:"loadavg1"`
+	LoadAvg5     *float64    `json:"load_avg_5,omitempty" yaml:"loadavg5"`
+	LoadAvg15    *float64    `json:"load_avg_15,omitempty" yaml:"loadavg15"`
+	MemoryTotal  *int64      `json:"memory_total,omitempty" yaml:"memorytotal"`
+	MemoryUsed   *int64      `json:"memory_used,omitempty" yaml:"memoryused"`
+	MemoryFree   *int64      `json:"memory_free,omitempty" yaml:"memoryfree"`
+	DiskTotal    *int64      `json:"disk_total,omitempty" yaml:"disktotal"`
+	DiskUsed     *int64      `json:"disk_used,omitempty" yaml:"diskused"`
+	DiskFree     *int64      `json:"disk_free,omitempty" yaml:"diskfree"`
+	ProcessCount *int        `json:"process_count,omitempty" yaml:"processcount"`
+	NetRxBytes   *int64      `json:"net_rx_bytes,omitempty" yaml:"netrxbytes"`
+	NetTxBytes   *int64      `json:"net_tx_bytes,omitempty" yaml:"nettxbytes"`
+	CPUTempC     *float64    `json:"cpu_temp_c,omitempty" yaml:"cputempc"`
+	GPUs         []GPUMetric `json:"gpus,omitempty" yaml:"gpus"`
+}
+
+// GPUMetric represents the metrics of one GPU, when the agent reports them
+type GPUMetric struct {
+	Index       int      `json:"index" yaml:"index"`
+	Name        string   `json:"name" yaml:"name"`
+	Utilization *float64 `json:"utilization,omitempty" yaml:"utilization"`
+	MemoryUsed  *int64   `json:"memory_used,omitempty" yaml:"memoryused"`
+	MemoryTotal *int64   `json:"memory_total,omitempty" yaml:"memorytotal"`
+	Temperature *float64 `json:"temperature,omitempty" yaml:"temperature"`
+}
+
+// maxGPUUtilization returns the highest utilization across all GPUs
+func (m *ServerMetrics) maxGPUUtilization() (float64, bool) {
+	var highest float64
+	found := false
+	for _, gpu := range m.GPUs {
+		if gpu.Utilization != nil && (!found || *gpu.Utilization > highest) {
+			highest = *gpu.Utilization
+			found = true
+		}
+	}
+	return highest, found
 }
 
 // MetricsHistory represents historical metrics
 type MetricsHistory struct {
-	ServerID string        `json:"server_id"`
-	Range    string        `json:"range"`
-	Data     []MetricsData `json:"data"`
+	ServerID string        `json:"server_id" yaml:"serverid"`
+	Range    string        `json:"range" yaml:"range"`
+	Data     []MetricsData `json:"data" yaml:"data"`
 }
 
 // MetricsData represents a single metrics data point
 type MetricsData struct {
-	CollectedAt time.Time `json:"collected_at"`
-	CPUUsage    *float64  `json:"cpu_usage,omitempty"`
-	MemoryUsed  *int64    `json:"memory_used,omitempty"`
-	DiskUsed    *int64    `json:"disk_used,omitempty"`
+	CollectedAt time.Time `json:"collected_at" yaml:"collectedat"`
+	CPUUsage    *float64  `json:"cpu_usage,omitempty" yaml:"cpuusage"`
+	MemoryUsed  *int64    `json:"memory_used,omitempty" yaml:"memoryused"`
+	DiskUsed    *int64    `json:"disk_used,omitempty" yaml:"diskused"`
 }
 
 // ============================================================================
@@ -162,10 +383,10 @@ func (c *Client) VerifyToken() (*VerifyResponse, error) {
 
 // VerifyResponse represents the verify token response
 type VerifyResponse struct {
-	Valid    bool   `json:"valid"`
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	Plan     string `json:"plan"`
+	Valid    bool   `json:"valid" yaml:"valid"`
+	UserID   string `json:"user_id" yaml:"userid"`
+	Username string `json:"username" yaml:"username"`
+	Plan     string `json:"plan" yaml:"plan"`
 }
 
 // GetCurrentUser gets the current user info
@@ -177,20 +398,119 @@ func (c *Client) GetCurrentUser() (*CurrentUserResponse, error) {
 	return &resp, nil
 }
 
+// RevokeToken invalidates the current token server-side
+func (c *Client) RevokeToken() error {
+	return c.Do("POST", "/api/auth/revoke", nil, nil)
+}
+
 // CurrentUserResponse represents the current user response
 type CurrentUserResponse struct {
-	User        User `json:"user"`
-	ServerCount int  `json:"server_count"`
-	ServerLimit int  `json:"server_limit"`
+	User        User `json:"user" yaml:"user"`
+	ServerCount int  `json:"server_count" yaml:"servercount"`
+	ServerLimit int  `json:"server_limit" yaml:"serverlimit"`
 }
 
-// ListServers lists all servers
-func (c *Client) ListServers() ([]Server, error) {
-	var servers []Server
-	if err := c.Do("GET", "/api/servers", nil, &servers); err != nil {
+// ListServers lists all servers. fields, when given, asks the API to
+// return only those attributes; see ServerListOptions.Fields.
+func (c *Client) ListServers(fields ...string) ([]Server, error) {
+	page, err := c.ListServersPage(ServerListOptions{Fields: fields})
+	if err != nil {
 		return nil, err
 	}
-	return servers, nil
+	return page.Servers, nil
+}
+
+// ServerListOptions are the pagination and projection parameters for
+// ListServersPage. Zero values are left out of the request.
+//
+// Fields lists the JSON attributes to return (e.g. "id", "name",
+// "metrics"). It only saves bandwidth: an API that ignores it returns full
+// servers, which decode the same way.
+type ServerListOptions struct {
+	Limit  int
+	Page   int
+	Fields []string
+}
+
+// ServerPage is one page of servers. Total is -1 when the API doesn't
+// report a total count.
+type ServerPage struct {
+	Servers []Server
+	Total   int
+}
+
+// serverPageResponse is the paginated list envelope; unpaginated
+// deployments return a bare array instead
+type serverPageResponse struct {
+	Servers []Server `json:"servers"`
+	Items   []Server `json:"items"`
+	Total   *int     `json:"total"`
+}
+
+// ListServersPage lists servers, forwarding limit and page to the API
+func (c *Client) ListServersPage(opts ServerListOptions) (*ServerPage, error) {
+	query := url.Values{}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Page > 0 {
+		query.Set("page", strconv.Itoa(opts.Page))
+	}
+	if len(opts.Fields) > 0 {
+		query.Set("fields", strings.Join(opts.Fields, ","))
+	}
+	path := "/api/servers"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var raw json.RawMessage
+	if err := c.Do("GET", path, nil, &raw); err != nil {
+		return nil, err
+	}
+
+	page := &ServerPage{Total: -1}
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(raw, &page.Servers); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		// A bare array is the whole list from an API that doesn't paginate,
+		// so cut the requested page out of it here
+		if opts.Limit > 0 {
+			page.Total = len(page.Servers)
+			page.Servers = pageOf(page.Servers, opts.Page, opts.Limit)
+		}
+		return page, nil
+	}
+
+	var resp serverPageResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	page.Servers = resp.Servers
+	if page.Servers == nil {
+		page.Servers = resp.Items
+	}
+	if resp.Total != nil {
+		page.Total = *resp.Total
+	}
+	return page, nil
+}
+
+// pageOf returns the servers on a 1-based page of the given size
+func pageOf(servers []Server, page, limit int) []Server {
+	if page < 1 {
+		page = 1
+	}
+	start := (page - 1) * limit
+	if start >= len(servers) {
+		return []Server{}
+	}
+	end := start + limit
+	if end > len(servers) {
+		end = len(servers)
+	}
+	return servers[start:end]
 }
 
 // CreateServer creates a new server
@@ -202,10 +522,16 @@ func (c *Client) CreateServer(name string) (*Server, error) {
 	return &server, nil
 }
 
-// GetServer gets a server by ID
-func (c *Client) GetServer(id string) (*Server, error) {
+// GetServer gets a server by ID. fields, when given, asks the API to
+// return only those attributes; an API that ignores it returns the full
+// server.
+func (c *Client) GetServer(id string, fields ...string) (*Server, error) {
+	path := "/api/servers/" + id
+	if len(fields) > 0 {
+		path += "?" + url.Values{"fields": {strings.Join(fields, ",")}}.Encode()
+	}
 	var server Server
-	if err := c.Do("GET", "/api/servers/"+id, nil, &server); err != nil {
+	if err := c.Do("GET", path, nil, &server); err != nil {
 		return nil, err
 	}
 	return &server, nil
@@ -220,11 +546,65 @@ func (c *Client) UpdateServer(id string, name string) (*Server, error) {
 	return &server, nil
 }
 
+// ServerUpdate holds the fields to change on a server; unset fields are left unchanged
+type ServerUpdate struct {
+	Name  *string           `json:"name,omitempty"`
+	Tags  map[string]string `json:"tags,omitempty"`
+	Notes *string           `json:"notes,omitempty"`
+}
+
+// UpdateServerFields applies a partial update to a server
+func (c *Client) UpdateServerFields(id string, update *ServerUpdate) (*Server, error) {
+	var server Server
+	if err := c.Do("PUT", "/api/servers/"+id, update, &server); err != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
+// ServerTags is the tag set returned by the tags endpoints
+type ServerTags struct {
+	Tags map[string]string `json:"tags" yaml:"tags"`
+}
+
+// AddServerTags sets tags on a server, replacing the values of existing keys
+func (c *Client) AddServerTags(id string, tags map[string]string) (map[string]string, error) {
+	var resp ServerTags
+	if err := c.Do("POST", "/api/servers/"+id+"/tags", ServerTags{Tags: tags}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Tags, nil
+}
+
+// RemoveServerTags removes tags from a server by key
+func (c *Client) RemoveServerTags(id string, keys []string) (map[string]string, error) {
+	var resp ServerTags
+	if err := c.Do("DELETE", "/api/servers/"+id+"/tags", map[string][]string{"keys": keys}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Tags, nil
+}
+
 // DeleteServer deletes a server
 func (c *Client) DeleteServer(id string) error {
 	return c.Do("DELETE", "/api/servers/"+id, nil, nil)
 }
 
+// Server collection states accepted by SetServerState
+const (
+	ServerStatePaused = "paused"
+	ServerStateActive = "active"
+)
+
+// SetServerState pauses or resumes metric collection for a server
+func (c *Client) SetServerState(id, state string) (*Server, error) {
+	var server Server
+	if err := c.Do("PUT", "/api/servers/"+id+"/state", map[string]string{"state": state}, &server); err != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
 // RegenerateAgentKey regenerates the agent key for a server
 func (c *Client) RegenerateAgentKey(id string) (*AgentKeyResponse, error) {
 	var resp AgentKeyResponse
@@ -236,22 +616,29 @@ func (c *Client) RegenerateAgentKey(id string) (*AgentKeyResponse, error) {
 
 // AgentKeyResponse represents the agent key response
 type AgentKeyResponse struct {
-	AgentKey string `json:"agent_key"`
+	AgentKey string `json:"agent_key" yaml:"agentkey"`
 }
 
-// GetInstallCommand gets the agent installation command
-func (c *Client) GetInstallCommand(id string) (*InstallCommandResponse, error) {
+// GetInstallCommand gets the agent installation command, optionally for a
+// specific target OS (empty means the server default)
+func (c *Client) GetInstallCommand(id, osType string) (*InstallCommandResponse, error) {
+	path := "/api/servers/" + id + "/install-command"
+	if osType != "" {
+		path += "?os=" + url.QueryEscape(osType)
+	}
 	var resp InstallCommandResponse
-	if err := c.Do("GET", "/api/servers/"+id+"/install-command", nil, &resp); err != nil {
+	if err := c.Do("GET", path, nil, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// InstallCommandResponse represents the install command response
+// InstallCommandResponse represents the install command response. Newer
+// API versions also return the command for every platform in Variants.
 type InstallCommandResponse struct {
-	Command  string `json:"command"`
-	AgentKey string `json:"agent_key"`
+	Command  string            `json:"command" yaml:"command"`
+	AgentKey string            `json:"agent_key" yaml:"agentkey"`
+	Variants map[string]string `json:"variants,omitempty" yaml:"variants,omitempty"`
 }
 
 // GetServerMetrics gets the latest metrics for a server
@@ -265,41 +652,113 @@ func (c *Client) GetServerMetrics(id string) (*MetricsResponse, error) {
 
 // MetricsResponse represents the metrics response
 type MetricsResponse struct {
-	Metrics *ServerMetrics `json:"metrics"`
+	Metrics *ServerMetrics `json:"metrics" yaml:"metrics"`
 }
 
-// GetServerHistory gets the metrics history for a server
-func (c *Client) GetServerHistory(id string, rangeStr string) (*MetricsHistory, error) {
+// GetServerHistory gets the metrics history for a server. An empty
+// resolution leaves downsampling to the server's default for the range.
+func (c *Client) GetServerHistory(id string, rangeStr string, resolution string) (*MetricsHistory, error) {
 	var resp MetricsHistory
-	path := "/api/servers/" + id + "/history"
-	if rangeStr != "" {
-		path += "?range=" + rangeStr
-	}
-	if err := c.Do("GET", path, nil, &resp); err != nil {
+	if err := c.Do("GET", historyPath(id, rangeStr, resolution), nil, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
+// StreamServerHistory calls fn for each history data point as it is
+// decoded from the response, without holding the whole range in memory
+func (c *Client) StreamServerHistory(id, rangeStr, resolution string, fn func(MetricsData) error) error {
+	return c.DoStream(historyPath(id, rangeStr, resolution), func(body io.Reader) error {
+		dec := json.NewDecoder(body)
+		if err := expectDelim(dec, '{'); err != nil {
+			return err
+		}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+			if key != "data" {
+				var skip json.RawMessage
+				if err := dec.Decode(&skip); err != nil {
+					return fmt.Errorf("failed to parse response: %w", err)
+				}
+				continue
+			}
+
+			tok, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+			if tok == nil {
+				continue // "data": null
+			}
+			if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+				return fmt.Errorf("failed to parse response: expected [, got %v", tok)
+			}
+			for dec.More() {
+				var point MetricsData
+				if err := dec.Decode(&point); err != nil {
+					return fmt.Errorf("failed to parse response: %w", err)
+				}
+				if err := fn(point); err != nil {
+					return err
+				}
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// expectDelim reads the next JSON token and checks it is the given delimiter
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("failed to parse response: expected %s, got %v", want, tok)
+	}
+	return nil
+}
+
+// historyPath builds the history endpoint path with its query
+func historyPath(id, rangeStr, resolution string) string {
+	query := url.Values{}
+	if rangeStr != "" {
+		query.Set("range", rangeStr)
+	}
+	if resolution != "" {
+		query.Set("resolution", resolution)
+	}
+	path := "/api/servers/" + id + "/history"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return path
+}
+
 // Helper methods for cleaner API calls
 
 // get performs a GET request
 func (c *Client) get(path string, result interface{}) error {
-	return c.Do("GET", "/api"+path, nil, result)
+	return c.Do("GET", joinURL("/api", path), nil, result)
 }
 
 // post performs a POST request
 func (c *Client) post(path string, body interface{}, result interface{}) error {
-	return c.Do("POST", "/api"+path, body, result)
+	return c.Do("POST", joinURL("/api", path), body, result)
 }
 
 // put performs a PUT request
 func (c *Client) put(path string, body interface{}, result interface{}) error {
-	return c.Do("PUT", "/api"+path, body, result)
+	return c.Do("PUT", joinURL("/api", path), body, result)
 }
 
 // delete performs a DELETE request
 func (c *Client) delete(path string) error {
-	return c.Do("DELETE", "/api"+path, nil, nil)
+	return c.Do("DELETE", joinURL("/api", path), nil, nil)
 }
-
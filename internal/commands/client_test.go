@@ -0,0 +1,58 @@
+package commands
+
+import "testing"
+
+func TestJoinURL(t *testing.T) {
+	tests := []struct {
+		base, path, want string
+	}{
+		{"https://api.example.com", "/api/servers", "https://api.example.com/api/servers"},
+		{"https://api.example.com/", "/api/servers", "https://api.example.com/api/servers"},
+		{"https://api.example.com///", "/api/servers", "https://api.example.com/api/servers"},
+		{"https://api.example.com", "api/servers", "https://api.example.com/api/servers"},
+		{"https://api.example.com/", "api/servers", "https://api.example.com/api/servers"},
+		{"https://api.example.com/vstats/", "/api/servers", "https://api.example.com/vstats/api/servers"},
+		{"https://api.example.com/", "", "https://api.example.com"},
+		{"https://api.example.com/api/", "?range=24h", "https://api.example.com/api?range=24h"},
+		{"https://api.example.com/", historyPath("abc", "24h", ""), "https://api.example.com/api/servers/abc/history?range=24h"},
+	}
+	for _, tt := range tests {
+		if got := joinURL(tt.base, tt.path); got != tt.want {
+			t.Errorf("joinURL(%q, %q) = %q, want %q", tt.base, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestHistoryPath(t *testing.T) {
+	tests := []struct {
+		id, rangeStr, resolution, want string
+	}{
+		{"abc", "", "", "/api/servers/abc/history"},
+		{"abc", "24h", "", "/api/servers/abc/history?range=24h"},
+		{"abc", "", "5m", "/api/servers/abc/history?resolution=5m"},
+		{"abc", "7d", "1h", "/api/servers/abc/history?range=7d&resolution=1h"},
+	}
+	for _, tt := range tests {
+		if got := historyPath(tt.id, tt.rangeStr, tt.resolution); got != tt.want {
+			t.Errorf("historyPath(%q, %q, %q) = %q, want %q", tt.id, tt.rangeStr, tt.resolution, got, tt.want)
+		}
+	}
+}
+
+func TestPageOf(t *testing.T) {
+	servers := make([]Server, 45)
+	tests := []struct {
+		page, limit, want int
+	}{
+		{1, 20, 20},
+		{2, 20, 20},
+		{3, 20, 5},
+		{4, 20, 0},
+		{0, 20, 20},
+	}
+	for _, tt := range tests {
+		if got := len(pageOf(servers, tt.page, tt.limit)); got != tt.want {
+			t.Errorf("pageOf(45 servers, %d, %d) has %d servers, want %d", tt.page, tt.limit, got, tt.want)
+		}
+	}
+}
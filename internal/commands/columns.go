@@ -0,0 +1,230 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// serverColumn is one selectable column of the server list table
+type serverColumn struct {
+	Key    string
+	Header string
+	Value  func(s Server, ptrNames map[string]string) string
+}
+
+// serverColumns lists every column server list can show, in --fields order
+var serverColumns = []serverColumn{
+	{"id", "ID", func(s Server, _ map[string]string) string { return s.ID }},
+	{"name", "NAME", func(s Server, _ map[string]string) string { return s.Name }},
+	{"status", "STATUS", func(s Server, _ map[string]string) string { return formatStatus(s.Status) }},
+	{"cpu", "CPU", func(s Server, _ map[string]string) string {
+		if s.Metrics == nil || s.Metrics.CPUUsage == nil {
+			return "-"
+		}
+		return formatThresholdPercent(MetricCPU, *s.Metrics.CPUUsage)
+	}},
+	{"mem", "MEM", func(s Server, _ map[string]string) string {
+		if s.Metrics == nil {
+			return "-"
+		}
+		if pct, ok := usagePercent(s.Metrics.MemoryUsed, s.Metrics.MemoryTotal); ok {
+			return formatThresholdPercent(MetricMemory, pct)
+		}
+		return "-"
+	}},
+	{"disk", "DISK", func(s Server, _ map[string]string) string {
+		if s.Metrics == nil {
+			return "-"
+		}
+		if pct, ok := usagePercent(s.Metrics.DiskUsed, s.Metrics.DiskTotal); ok {
+			return formatThresholdPercent(MetricDisk, pct)
+		}
+		return "-"
+	}},
+	{"gpus", "GPUS", func(s Server, _ map[string]string) string {
+		if s.Metrics == nil || len(s.Metrics.GPUs) == 0 {
+			return "-"
+		}
+		return strconv.Itoa(len(s.Metrics.GPUs))
+	}},
+	{"gpu", "GPU", func(s Server, _ map[string]string) string {
+		if s.Metrics == nil {
+			return "-"
+		}
+		if pct, ok := s.Metrics.maxGPUUtilization(); ok {
+			return formatThresholdPercent(MetricGPU, pct)
+		}
+		return "-"
+	}},
+	{"ip", "IP", func(s Server, _ map[string]string) string { return ptrString(s.IPAddress) }},
+	{"ptr", "PTR", func(s Server, ptrNames map[string]string) string {
+		if ptr, ok := ptrNames[ptrString(s.IPAddress)]; ok {
+			return ptr
+		}
+		return "-"
+	}},
+	{"hostname", "HOSTNAME", func(s Server, _ map[string]string) string { return ptrString(s.Hostname) }},
+	{"os", "OS", func(s Server, _ map[string]string) string { return ptrString(s.OSType) }},
+	{"agent", "AGENT", func(s Server, _ map[string]string) string { return ptrString(s.AgentVersion) }},
+	{"tags", "TAGS", func(s Server, _ map[string]string) string { return formatTags(s.Tags) }},
+	{"last-seen", "LAST SEEN", func(s Server, _ map[string]string) string { return formatTimestamp(s.LastSeenAt) }},
+}
+
+// serverColumnAPIFields maps each column to the API attribute it is
+// rendered from, for requesting only what --fields displays
+var serverColumnAPIFields = map[string]string{
+	"id":        "id",
+	"name":      "name",
+	"status":    "status",
+	"cpu":       "metrics",
+	"mem":       "metrics",
+	"disk":      "metrics",
+	"gpus":      "metrics",
+	"gpu":       "metrics",
+	"ip":        "ip_address",
+	"ptr":       "ip_address",
+	"hostname":  "hostname",
+	"os":        "os_type",
+	"agent":     "agent_version",
+	"tags":      "tags",
+	"last-seen": "last_seen_at",
+}
+
+// serverAPIFields returns the API attributes needed to render columns plus
+// any extra attributes, without duplicates. The id is always included.
+func serverAPIFields(columns []serverColumn, extra ...string) []string {
+	fields := []string{"id"}
+	seen := map[string]bool{"id": true}
+	add := func(field string) {
+		if !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+	for _, c := range columns {
+		add(serverColumnAPIFields[c.Key])
+	}
+	for _, field := range extra {
+		add(field)
+	}
+	return fields
+}
+
+// serverListAPIFields returns the API attributes server list needs to show
+// columns: those rendered plus any its sort, group, and filter flags read
+func serverListAPIFields(cmd *cobra.Command, columns []serverColumn) []string {
+	var extra []string
+	switch sortKey, _ := cmd.Flags().GetString("sort"); sortKey {
+	case "cpu", "mem", "disk":
+		extra = append(extra, "metrics")
+	case "last-seen":
+		extra = append(extra, "last_seen_at")
+	case "status":
+		extra = append(extra, "status")
+	default:
+		extra = append(extra, "name")
+	}
+	switch groupBy, _ := cmd.Flags().GetString("group-by"); groupBy {
+	case "status":
+		extra = append(extra, "status")
+	case "os":
+		extra = append(extra, "os_type")
+	case "tag":
+		extra = append(extra, "tags")
+	}
+	if status, _ := cmd.Flags().GetString("status"); status != "" {
+		extra = append(extra, "status")
+	}
+	if text, _ := cmd.Flags().GetString("filter"); text != "" {
+		extra = append(extra, "name", "hostname", "ip_address")
+	}
+	if tags, _ := cmd.Flags().GetStringArray("tag"); len(tags) > 0 {
+		extra = append(extra, "tags")
+	}
+	if metricFilterFromFlags(cmd).active() {
+		extra = append(extra, "metrics")
+	}
+	return serverAPIFields(columns, extra...)
+}
+
+// serverColumnKeys returns the names accepted by --fields
+func serverColumnKeys() []string {
+	keys := make([]string, len(serverColumns))
+	for i, c := range serverColumns {
+		keys[i] = c.Key
+	}
+	return keys
+}
+
+// lookupServerColumns resolves column keys, erroring on unknown ones
+func lookupServerColumns(keys []string) ([]serverColumn, error) {
+	columns := make([]serverColumn, 0, len(keys))
+	for _, key := range keys {
+		key = strings.ToLower(strings.TrimSpace(key))
+		found := false
+		for _, c := range serverColumns {
+			if c.Key == key {
+				columns = append(columns, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown field: %s (available: %s)", key, strings.Join(serverColumnKeys(), ", "))
+		}
+	}
+	return columns, nil
+}
+
+// defaultServerColumnKeys returns the columns shown without --fields
+func defaultServerColumnKeys(showGPU, resolveDNS bool) []string {
+	keys := []string{"name", "status", "cpu", "mem", "disk"}
+	if showGPU {
+		keys = append(keys, "gpus", "gpu")
+	}
+	keys = append(keys, "ip")
+	if resolveDNS {
+		keys = append(keys, "ptr")
+	}
+	return append(keys, "last-seen")
+}
+
+// widenServerColumns adds the -o wide columns to columns: ID first, then
+// hostname, OS, and agent version before LAST SEEN (or at the end). Columns
+// already present are not repeated.
+func widenServerColumns(columns []serverColumn) []serverColumn {
+	var extra []serverColumn
+	for _, key := range []string{"hostname", "os", "agent"} {
+		if !hasServerColumn(columns, key) {
+			c, _ := lookupServerColumns([]string{key})
+			extra = append(extra, c...)
+		}
+	}
+
+	wide := make([]serverColumn, 0, len(columns)+len(extra)+1)
+	if !hasServerColumn(columns, "id") {
+		c, _ := lookupServerColumns([]string{"id"})
+		wide = append(wide, c...)
+	}
+	for _, c := range columns {
+		if c.Key == "last-seen" {
+			wide = append(wide, extra...)
+			extra = nil
+		}
+		wide = append(wide, c)
+	}
+	return append(wide, extra...)
+}
+
+// hasServerColumn reports whether key is among the columns
+func hasServerColumn(columns []serverColumn, key string) bool {
+	for _, c := range columns {
+		if c.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// conditionOperators are the supported comparison operators, longest first
+// so that "<=" is matched before "<"
+var conditionOperators = []string{"<=", ">=", "==", "!=", "<", ">"}
+
+// conditionClause is a single comparison such as cpu<20
+type conditionClause struct {
+	Metric string
+	Op     string
+	Value  float64
+}
+
+// Condition is a conjunction of metric comparisons, e.g. "cpu<20 && mem<80"
+type Condition struct {
+	Expr    string
+	Clauses []conditionClause
+}
+
+// parseCondition parses an expression of clauses joined by && or commas.
+// Each clause is <metric><op><number>, where metric is one of cpu, mem,
+// disk (percentages), load1, load5, load15, or processes.
+func parseCondition(expr string) (*Condition, error) {
+	cond := &Condition{Expr: expr}
+
+	parts := strings.FieldsFunc(strings.ReplaceAll(expr, "&&", ","), func(r rune) bool { return r == ',' })
+	for _, part := range parts {
+		part = strings.ReplaceAll(part, " ", "")
+		if part == "" {
+			continue
+		}
+
+		clause, err := parseConditionClause(part)
+		if err != nil {
+			return nil, err
+		}
+		cond.Clauses = append(cond.Clauses, clause)
+	}
+
+	if len(cond.Clauses) == 0 {
+		return nil, fmt.Errorf("empty condition")
+	}
+	return cond, nil
+}
+
+// parseConditionClause parses a single <metric><op><number> clause
+func parseConditionClause(s string) (conditionClause, error) {
+	for _, op := range conditionOperators {
+		idx := strings.Index(s, op)
+		if idx <= 0 {
+			continue
+		}
+
+		metric := strings.ToLower(s[:idx])
+		if !isConditionMetric(metric) {
+			return conditionClause{}, fmt.Errorf("unknown metric %q in condition (use cpu, mem, disk, load1, load5, load15, processes)", metric)
+		}
+
+		value, err := strconv.ParseFloat(strings.TrimSuffix(s[idx+len(op):], "%"), 64)
+		if err != nil {
+			return conditionClause{}, fmt.Errorf("invalid number in condition %q", s)
+		}
+		return conditionClause{Metric: metric, Op: op, Value: value}, nil
+	}
+	return conditionClause{}, fmt.Errorf("invalid condition %q (expected e.g. cpu<20)", s)
+}
+
+// isConditionMetric reports whether metric can be used in a condition
+func isConditionMetric(metric string) bool {
+	switch metric {
+	case "cpu", "mem", "memory", "disk", "load1", "load5", "load15", "processes":
+		return true
+	}
+	return false
+}
+
+// conditionMetricValue extracts a metric's value, reporting false if the
+// server did not report it
+func conditionMetricValue(metric string, m *ServerMetrics) (float64, bool) {
+	if m == nil {
+		return 0, false
+	}
+
+	fromFloat := func(f *float64) (float64, bool) {
+		if f == nil {
+			return 0, false
+		}
+		return *f, true
+	}
+
+	switch metric {
+	case "cpu":
+		return fromFloat(m.CPUUsage)
+	case "mem", "memory":
+		return usagePercent(m.MemoryUsed, m.MemoryTotal)
+	case "disk":
+		return usagePercent(m.DiskUsed, m.DiskTotal)
+	case "load1":
+		return fromFloat(m.LoadAvg1)
+	case "load5":
+		return fromFloat(m.LoadAvg5)
+	case "load15":
+		return fromFloat(m.LoadAvg15)
+	case "processes":
+		if m.ProcessCount == nil {
+			return 0, false
+		}
+		return float64(*m.ProcessCount), true
+	}
+	return 0, false
+}
+
+// Eval reports whether every clause holds for the given metrics. Clauses
+// whose metric is missing are treated as not satisfied.
+func (c *Condition) Eval(m *ServerMetrics) bool {
+	for _, clause := range c.Clauses {
+		value, ok := conditionMetricValue(clause.Metric, m)
+		if !ok || !compare(value, clause.Op, clause.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Describe renders the current value of each metric in the condition
+func (c *Condition) Describe(m *ServerMetrics) string {
+	parts := make([]string, 0, len(c.Clauses))
+	for _, clause := range c.Clauses {
+		value, ok := conditionMetricValue(clause.Metric, m)
+		if !ok {
+			parts = append(parts, clause.Metric+"=-")
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%.1f", clause.Metric, value))
+	}
+	return strings.Join(parts, " ")
+}
+
+// compare applies a comparison operator
+func compare(a float64, op string, b float64) bool {
+	switch op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	}
+	return false
+}
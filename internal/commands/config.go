@@ -1,10 +1,14 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -16,23 +20,60 @@ const (
 
 // Config represents the CLI configuration
 type Config struct {
-	CloudURL  string `yaml:"cloud_url" json:"cloud_url"`
-	Token     string `yaml:"token,omitempty" json:"token,omitempty"`
-	Username  string `yaml:"username,omitempty" json:"username,omitempty"`
-	ExpiresAt int64  `yaml:"expires_at,omitempty" json:"expires_at,omitempty"`
+	CloudURL              string            `yaml:"cloud_url" json:"cloud_url"`
+	Token                 string            `yaml:"token,omitempty" json:"token,omitempty"`
+	Username              string            `yaml:"username,omitempty" json:"username,omitempty"`
+	ExpiresAt             int64             `yaml:"expires_at,omitempty" json:"expires_at,omitempty"`
+	RefreshToken          string            `yaml:"refresh_token,omitempty" json:"refresh_token,omitempty"`
+	OIDCIssuer            string            `yaml:"oidc_issuer,omitempty" json:"oidc_issuer,omitempty"`
+	OIDCClientID          string            `yaml:"oidc_client_id,omitempty" json:"oidc_client_id,omitempty"`
+	CredentialStore       string            `yaml:"credential_store,omitempty" json:"credential_store,omitempty"`
+	CacheTTL              string            `yaml:"cache_ttl,omitempty" json:"cache_ttl,omitempty"`
+	CacheSWR              string            `yaml:"cache_swr,omitempty" json:"cache_swr,omitempty"`
+	RequestTimeoutSeconds *int              `yaml:"request_timeout_seconds,omitempty" json:"request_timeout_seconds,omitempty"`
+	Insecure              bool              `yaml:"insecure,omitempty" json:"insecure,omitempty"`
+	CACert                string            `yaml:"ca_cert,omitempty" json:"ca_cert,omitempty"`
+	Theme                 ThemeConfig       `yaml:"theme,omitempty" json:"theme,omitempty"`
+	Thresholds            ThresholdConfig   `yaml:"thresholds,omitempty" json:"thresholds,omitempty"`
+	Ranges                map[string]string `yaml:"ranges,omitempty" json:"ranges,omitempty"`
 }
 
 var cfg = &Config{
 	CloudURL: DefaultCloudURL,
 }
 
-// GetConfigDir returns the configuration directory
+// GetConfigDir returns the configuration directory. On Windows it is
+// ~/.vstats. Elsewhere the directory holding a config.yaml wins, checking
+// $XDG_CONFIG_HOME/vstats (default ~/.config/vstats) before the legacy
+// ~/.vstats; without one, an existing directory is used in the same order,
+// and new installs get the XDG location.
 func GetConfigDir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, ".vstats"), nil
+	legacy := filepath.Join(home, ".vstats")
+	if runtime.GOOS == "windows" {
+		return legacy, nil
+	}
+
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if !filepath.IsAbs(base) {
+		// The XDG spec says relative paths are invalid and must be ignored
+		base = filepath.Join(home, ".config")
+	}
+	xdg := filepath.Join(base, "vstats")
+	for _, dir := range []string{xdg, legacy} {
+		if _, err := os.Stat(filepath.Join(dir, "config.yaml")); err == nil {
+			return dir, nil
+		}
+	}
+	for _, dir := range []string{xdg, legacy} {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, nil
+		}
+	}
+	return xdg, nil
 }
 
 // GetConfigPath returns the configuration file path
@@ -77,7 +118,8 @@ func SaveConfig() error {
 		return err
 	}
 
-	data, err := yaml.Marshal(cfg)
+	onDisk := storeKeyringTokens()
+	data, err := yaml.Marshal(&onDisk)
 	if err != nil {
 		return err
 	}
@@ -90,9 +132,56 @@ func GetConfig() *Config {
 	return cfg
 }
 
+// Environment variables that override the stored configuration
+const (
+	envToken    = "VSTATS_TOKEN"
+	envCloudURL = "VSTATS_CLOUD_URL"
+)
+
+// tokenFromEnv reports whether VSTATS_TOKEN supplies the token
+func tokenFromEnv() bool {
+	return os.Getenv(envToken) != ""
+}
+
+// currentToken returns the bearer token: VSTATS_TOKEN if set, otherwise
+// the stored token, loaded from the keyring on first use when the keyring
+// credential store is enabled. The env token is never saved to disk.
+func currentToken() string {
+	if token := os.Getenv(envToken); token != "" {
+		return token
+	}
+	loadKeyringTokens()
+	return cfg.Token
+}
+
+// cloudURLOverride is the cloud URL from --cloud-url or VSTATS_CLOUD_URL.
+// It is kept apart from cfg so an override is never saved to disk.
+var cloudURLOverride string
+
+// currentCloudURL returns the cloud URL to use: the --cloud-url or
+// VSTATS_CLOUD_URL override if set, otherwise the stored cloud_url
+func currentCloudURL() string {
+	if cloudURLOverride != "" {
+		return cloudURLOverride
+	}
+	return cfg.CloudURL
+}
+
+// cloudURLSource names where currentCloudURL came from
+func cloudURLSource() string {
+	switch {
+	case cloudURLOverride == "":
+		return "config"
+	case cloudURL != "":
+		return "--cloud-url"
+	default:
+		return envCloudURL
+	}
+}
+
 // IsLoggedIn checks if user is logged in
 func IsLoggedIn() bool {
-	return cfg.Token != ""
+	return currentToken() != ""
 }
 
 // configCmd represents the config command
@@ -113,7 +202,7 @@ var configShowCmd = &cobra.Command{
 			LoggedIn  bool   `yaml:"logged_in" json:"logged_in"`
 			ExpiresAt int64  `yaml:"expires_at,omitempty" json:"expires_at,omitempty"`
 		}{
-			CloudURL:  cfg.CloudURL,
+			CloudURL:  currentCloudURL(),
 			Username:  cfg.Username,
 			LoggedIn:  IsLoggedIn(),
 			ExpiresAt: cfg.ExpiresAt,
@@ -121,11 +210,13 @@ var configShowCmd = &cobra.Command{
 
 		switch outputFmt {
 		case "json":
-			data, _ := json.MarshalIndent(display, "", "  ")
-			fmt.Println(string(data))
+			return OutputJSON(display)
+		case "jsonl":
+			return OutputJSONL(display)
 		case "yaml":
-			data, _ := yaml.Marshal(display)
-			fmt.Print(string(data))
+			return OutputYAML(display)
+		case "template":
+			return OutputTemplate(display)
 		default:
 			fmt.Println("vStats CLI Configuration")
 			fmt.Println("========================")
@@ -143,16 +234,67 @@ var configSetCmd = &cobra.Command{
 	Long: `Set a configuration value.
 
 Available keys:
-  cloud_url   The vStats Cloud API URL`,
+  cloud_url   The vStats Cloud API URL
+  cache_ttl   How long a cached server list is fresh (e.g. 30s, 0 disables)
+  cache_swr   How long a stale server list may be served while it is
+              refreshed in the background (e.g. 5m)
+  theme       Built-in color theme: dark (default), light, colorblind
+  theme.<role>
+              Override one color by name (red, cyan, ...) or 256-color
+              code (0-255). Roles: header, online, offline, pending,
+              unknown, warning, critical
+  thresholds.<metric>.<level>
+              Percentage at which a metric is warning or critical.
+              Metrics: cpu, memory, disk, gpu. Levels: warning (default 75),
+              critical (default 90)
+  ranges.<name>
+              A named history range preset usable as --range <name>,
+              e.g. ranges.incident 6h
+  request_timeout_seconds
+              HTTP request timeout in seconds (default 30, 0 disables);
+              --request-timeout overrides it for one command
+  ca_cert     PEM file of extra CA certificates to trust, for a
+              self-hosted Cloud behind a private CA
+  insecure    true to skip TLS certificate verification (unsafe; a
+              warning is printed on every command while it is on)
+  oidc_issuer OIDC issuer URL used by 'vstats login' for SSO
+  oidc_client_id
+              OIDC client ID for SSO login (default vstats-cli)
+  credential_store
+              Where the login token is kept: file (default, in this
+              config file) or keyring (macOS Keychain, Windows Credential
+              Manager, or libsecret on Linux)
+
+The cloud URL may also point at a local service listening on a Unix
+domain socket, using the unix:// scheme followed by the socket path:
+
+  vstats config set cloud_url unix:///var/run/vstats.sock
+
+Use --test with cloud_url to check the new URL before saving it. When
+logged in, the current token is verified against the new URL; otherwise
+the URL is only checked for reachability. If the test fails the value is
+not saved unless --force is also given.`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		key, value := args[0], args[1]
 
-		switch key {
-		case "cloud_url":
-			cfg.CloudURL = value
-		default:
-			return fmt.Errorf("unknown configuration key: %s", key)
+		if key == "cloud_url" {
+			value = strings.TrimRight(value, "/")
+			if err := validateCloudURL(value); err != nil {
+				return err
+			}
+			if test, _ := cmd.Flags().GetBool("test"); test {
+				force, _ := cmd.Flags().GetBool("force")
+				if err := testCloudURL(value); err != nil {
+					if !force {
+						return fmt.Errorf("connectivity test failed: %w. Use --force to save anyway", err)
+					}
+					fmt.Fprintf(os.Stderr, "Warning: connectivity test failed: %v\n", err)
+				}
+			}
+		}
+		if err := setConfigValue(key, value); err != nil {
+			return err
 		}
 
 		if err := SaveConfig(); err != nil {
@@ -164,9 +306,220 @@ Available keys:
 	},
 }
 
+// setConfigValue validates and applies one configuration key
+func setConfigValue(key, value string) error {
+	if err := validateConfigKey(key); err != nil {
+		return err
+	}
+
+	switch key {
+	case "cloud_url":
+		value = strings.TrimRight(value, "/")
+		if err := validateCloudURL(value); err != nil {
+			return err
+		}
+		cfg.CloudURL = value
+	case "cache_ttl", "cache_swr":
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("invalid duration for %s: %s", key, value)
+		}
+		if key == "cache_ttl" {
+			cfg.CacheTTL = value
+		} else {
+			cfg.CacheSWR = value
+		}
+	case "request_timeout_seconds":
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 0 {
+			return fmt.Errorf("invalid request_timeout_seconds: %s (use a whole number of seconds, 0 for no timeout)", value)
+		}
+		cfg.RequestTimeoutSeconds = &seconds
+	case "oidc_issuer":
+		cfg.OIDCIssuer = strings.TrimRight(value, "/")
+	case "oidc_client_id":
+		cfg.OIDCClientID = value
+	case "insecure":
+		insecure, err := parseBoolValue(key, value)
+		if err != nil {
+			return err
+		}
+		cfg.Insecure = insecure
+	case "ca_cert":
+		path, err := filepath.Abs(value)
+		if err != nil {
+			return fmt.Errorf("invalid ca_cert: %w", err)
+		}
+		if _, err := loadCACertPool(path); err != nil {
+			return err
+		}
+		cfg.CACert = path
+	case "credential_store":
+		switch value {
+		case "file":
+			// Pull the token out of the keyring so it is written to the file
+			loadKeyringTokens()
+			cfg.CredentialStore = ""
+		case credentialStoreKeyring:
+			cfg.CredentialStore = value
+		default:
+			return fmt.Errorf("invalid credential_store: %s (use file or keyring)", value)
+		}
+	default:
+		switch {
+		case key == "theme" || strings.HasPrefix(key, "theme."):
+			return setThemeValue(key, value)
+		case strings.HasPrefix(key, "thresholds."):
+			return setThresholdValue(key, value)
+		case strings.HasPrefix(key, "ranges."):
+			return setRangePreset(key, value)
+		}
+		return fmt.Errorf("unknown configuration key: %s", key)
+	}
+	return nil
+}
+
+// validateCloudURL checks that a cloud URL is an absolute http(s) URL
+// with a host, or a unix:// socket path
+func validateCloudURL(rawURL string) error {
+	if strings.HasPrefix(rawURL, unixSocketScheme) {
+		if _, ok := parseUnixSocketURL(rawURL); !ok {
+			return fmt.Errorf("invalid cloud_url %q: unix:// must be followed by a socket path", rawURL)
+		}
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid cloud_url %q: %w", rawURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid cloud_url %q: must start with https:// or http://", rawURL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid cloud_url %q: missing host", rawURL)
+	}
+	return nil
+}
+
+// configValidateCmd sanity-checks the configuration
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration for mistakes",
+	Long: `Check the configuration for mistakes that cause confusing request
+errors:
+  - cloud_url must be an http:// or https:// URL with a host, or a
+    unix:// socket path
+  - a trailing slash on cloud_url is flagged; requests ignore it, but
+    other tools reading the config may not
+  - each metric's warning threshold must be below its critical one
+  - when logged in, the token is verified against cloud_url
+
+The URL checked is the one requests use, so a --cloud-url or
+VSTATS_CLOUD_URL override is validated instead of the stored value.
+
+Exits non-zero if a problem is found; warnings alone don't fail.
+
+Examples:
+  vstats config validate`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		problems := 0
+		report := func(ok bool, key, detail string) {
+			mark := color(themeColor(RoleOnline), "✓")
+			if !ok {
+				mark = color(themeColor(RoleCritical), "✗")
+				problems++
+			}
+			fmt.Printf("%s %s: %s\n", mark, key, detail)
+		}
+		warn := func(key, detail string) {
+			fmt.Printf("%s %s: %s\n", color(themeColor(RoleWarning), "!"), key, detail)
+		}
+
+		// Check the URL requests will go to, and say where it came from
+		target := currentCloudURL()
+		urlErr := validateCloudURL(target)
+		if urlErr != nil {
+			report(false, "cloud_url", fmt.Sprintf("%s (from %s)", urlErr, cloudURLSource()))
+		} else {
+			report(true, "cloud_url", fmt.Sprintf("%s (from %s)", target, cloudURLSource()))
+		}
+		if strings.HasSuffix(target, "/") {
+			fix := "remove it with 'vstats doctor --fix'"
+			if cloudURLSource() != "config" {
+				fix = "remove it from " + cloudURLSource()
+			}
+			warn("cloud_url", "trailing slash (ignored by vstats); "+fix)
+		}
+
+		for _, metric := range thresholdMetrics {
+			if err := checkThresholdOrder(metric); err != nil {
+				report(false, "thresholds."+metric, err.Error())
+			}
+		}
+
+		switch {
+		case !IsLoggedIn():
+			warn("token", "not logged in, skipping verification")
+		case urlErr != nil:
+			warn("token", "skipping verification until cloud_url is fixed")
+		default:
+			resp, err := NewClient().VerifyToken()
+			switch {
+			case err != nil:
+				report(false, "token", fmt.Sprintf("verification failed: %v", err))
+			case !resp.Valid:
+				report(false, "token", "not valid at "+target+"; run 'vstats login'")
+			default:
+				report(true, "token", "valid for "+resp.Username+" at "+target)
+			}
+		}
+
+		if problems > 0 {
+			fmt.Println()
+			return fmt.Errorf("%d problem(s) found", problems)
+		}
+		return nil
+	},
+}
+
+// testCloudURL checks that a cloud URL is reachable and, when logged in,
+// that the current token is accepted there
+func testCloudURL(cloudURL string) error {
+	client := newClientFor(cloudURL, currentToken())
+
+	if !IsLoggedIn() {
+		if err := client.Probe(); err != nil {
+			return err
+		}
+		fmt.Printf("✓ %s is reachable\n", cloudURL)
+		return nil
+	}
+
+	resp, err := client.VerifyToken()
+	if err != nil {
+		return err
+	}
+	if !resp.Valid {
+		return fmt.Errorf("current token is not valid at %s", cloudURL)
+	}
+	fmt.Printf("✓ Current token is valid at %s (user: %s)\n", cloudURL, resp.Username)
+	return nil
+}
+
 var configPathCmd = &cobra.Command{
 	Use:   "path",
 	Short: "Show configuration file path",
+	Long: `Show the path of the configuration file.
+
+The config directory is resolved in this order (on Windows it is always
+~/.vstats):
+  1. $XDG_CONFIG_HOME/vstats, or ~/.config/vstats when XDG_CONFIG_HOME is
+     unset, if that directory exists
+  2. ~/.vstats, if it exists (configs created by older releases)
+  3. the XDG location from step 1, for new installs
+
+The response cache and update check cache live in the same directory.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		path, err := GetConfigPath()
 		if err != nil {
@@ -181,5 +534,8 @@ func init() {
 	configCmd.AddCommand(configShowCmd)
 	configCmd.AddCommand(configSetCmd)
 	configCmd.AddCommand(configPathCmd)
-}
+	configCmd.AddCommand(configValidateCmd)
 
+	configSetCmd.Flags().Bool("test", false, "test connectivity to a new cloud_url before saving it")
+	configSetCmd.Flags().BoolP("force", "f", false, "save the value even if the connectivity test fails")
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestGetConfigDirPrefersExistingConfigFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows always uses ~/.vstats")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	legacy := filepath.Join(home, ".vstats")
+	xdg := filepath.Join(home, ".config", "vstats")
+
+	check := func(want string) {
+		t.Helper()
+		if got, err := GetConfigDir(); err != nil || got != want {
+			t.Errorf("GetConfigDir() = %q, %v; want %q", got, err, want)
+		}
+	}
+	write := func(dir string) {
+		t.Helper()
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("username: x\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// New installs get the XDG location
+	check(xdg)
+
+	// A legacy config wins over an empty XDG directory
+	write(legacy)
+	if err := os.MkdirAll(xdg, 0700); err != nil {
+		t.Fatal(err)
+	}
+	check(legacy)
+
+	// Once the XDG directory has a config, it is used
+	write(xdg)
+	check(xdg)
+}
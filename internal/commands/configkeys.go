@@ -0,0 +1,202 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// configKeys are the fixed keys accepted by config set, get, and unset.
+// theme.<role>, thresholds.<metric>.<level>, and ranges.<name> are also
+// accepted.
+var configKeys = []string{"cloud_url", "cache_ttl", "cache_swr", "request_timeout_seconds", "insecure", "ca_cert", "theme", "oidc_issuer", "oidc_client_id", "credential_store"}
+
+// validateConfigKey checks a key against the same set config set accepts
+func validateConfigKey(key string) error {
+	for _, k := range configKeys {
+		if k == key {
+			return nil
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(key, "theme."):
+		if role := strings.TrimPrefix(key, "theme."); !isThemeRole(role) {
+			return fmt.Errorf("unknown theme role: %s (available: %s)", role, strings.Join(themeRoles, ", "))
+		}
+		return nil
+	case strings.HasPrefix(key, "thresholds."):
+		parts := strings.Split(key, ".")
+		if len(parts) != 3 || thresholdLevels(parts[1]) == nil || (parts[2] != "warning" && parts[2] != "critical") {
+			return fmt.Errorf("invalid threshold key: %s (use thresholds.<%s>.<warning|critical>)", key, strings.Join(thresholdMetrics, "|"))
+		}
+		return nil
+	case strings.HasPrefix(key, "ranges."):
+		if name := strings.TrimPrefix(key, "ranges."); name == "" || strings.Contains(name, ".") {
+			return fmt.Errorf("invalid range preset name: %q", name)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown configuration key: %s", key)
+}
+
+// getConfigValue returns the effective value of a key, falling back to its
+// default. ok is false when the key has neither a value nor a default.
+func getConfigValue(key string) (value string, ok bool, err error) {
+	if err := validateConfigKey(key); err != nil {
+		return "", false, err
+	}
+
+	switch key {
+	case "cloud_url":
+		value = cfg.CloudURL
+	case "cache_ttl":
+		value = cfg.CacheTTL
+	case "cache_swr":
+		value = cfg.CacheSWR
+	case "request_timeout_seconds":
+		seconds := int(DefaultRequestTimeout / time.Second)
+		if cfg.RequestTimeoutSeconds != nil {
+			seconds = *cfg.RequestTimeoutSeconds
+		}
+		value = strconv.Itoa(seconds)
+	case "insecure":
+		value = strconv.FormatBool(cfg.Insecure)
+	case "ca_cert":
+		value = cfg.CACert
+	case "theme":
+		value = cfg.Theme.Name
+		if value == "" {
+			value = defaultThemeName
+		}
+	case "oidc_issuer":
+		value = cfg.OIDCIssuer
+	case "oidc_client_id":
+		value = cfg.OIDCClientID
+	case "credential_store":
+		value = cfg.CredentialStore
+		if value == "" {
+			value = "file"
+		}
+	default:
+		switch {
+		case strings.HasPrefix(key, "theme."):
+			value = cfg.Theme.Colors[strings.TrimPrefix(key, "theme.")]
+		case strings.HasPrefix(key, "thresholds."):
+			parts := strings.Split(key, ".")
+			levels := thresholdsFor(parts[1])
+			level := levels.Warning
+			if parts[2] == "critical" {
+				level = levels.Critical
+			}
+			value = strconv.FormatFloat(level, 'f', -1, 64)
+		case strings.HasPrefix(key, "ranges."):
+			value = cfg.Ranges[strings.TrimPrefix(key, "ranges.")]
+		}
+	}
+	return value, value != "", nil
+}
+
+// unsetConfigValue resets a key to its default
+func unsetConfigValue(key string) error {
+	if err := validateConfigKey(key); err != nil {
+		return err
+	}
+
+	switch key {
+	case "cloud_url":
+		cfg.CloudURL = DefaultCloudURL
+	case "cache_ttl":
+		cfg.CacheTTL = ""
+	case "cache_swr":
+		cfg.CacheSWR = ""
+	case "request_timeout_seconds":
+		cfg.RequestTimeoutSeconds = nil
+	case "insecure":
+		cfg.Insecure = false
+	case "ca_cert":
+		cfg.CACert = ""
+	case "theme":
+		cfg.Theme.Name = ""
+	case "oidc_issuer":
+		cfg.OIDCIssuer = ""
+	case "oidc_client_id":
+		cfg.OIDCClientID = ""
+	case "credential_store":
+		// Pull the token out of the keyring so it is written to the file
+		loadKeyringTokens()
+		cfg.CredentialStore = ""
+	default:
+		switch {
+		case strings.HasPrefix(key, "theme."):
+			delete(cfg.Theme.Colors, strings.TrimPrefix(key, "theme."))
+		case strings.HasPrefix(key, "thresholds."):
+			parts := strings.Split(key, ".")
+			levels := thresholdLevels(parts[1])
+			if parts[2] == "critical" {
+				levels.Critical = 0
+			} else {
+				levels.Warning = 0
+			}
+		case strings.HasPrefix(key, "ranges."):
+			delete(cfg.Ranges, strings.TrimPrefix(key, "ranges."))
+		}
+	}
+	return nil
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a configuration value",
+	Long: `Print the value of one configuration key, for use in scripts.
+
+Keys are the same as for 'vstats config set'. Keys with a default (such as
+cloud_url, theme, and thresholds) print the default when unset; other
+unset keys print nothing and exit with an error.
+
+Examples:
+  vstats config get cloud_url
+  vstats config get thresholds.cpu.warning`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, ok, err := getConfigValue(args[0])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%s is not set", args[0])
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Reset a configuration value to its default",
+	Long: `Reset one configuration key to its default, e.g. cloud_url back to
+` + DefaultCloudURL + `. Keys are the same as for 'vstats config set'.
+
+Examples:
+  vstats config unset cloud_url
+  vstats config unset ranges.incident`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := unsetConfigValue(args[0]); err != nil {
+			return err
+		}
+		if err := SaveConfig(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ Configuration reset: %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+}
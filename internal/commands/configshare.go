@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// secretConfigKeys are never exported or imported
+var secretConfigKeys = map[string]bool{
+	"token":            true,
+	"refresh_token":    true,
+	"username":         true,
+	"expires_at":       true,
+	"credential_store": true,
+	// Never let a shared file turn off certificate checks
+	"insecure": true,
+}
+
+// shareableConfig flattens the non-secret configuration into the keys
+// accepted by 'config set'
+func shareableConfig() map[string]string {
+	values := make(map[string]string)
+	add := func(key, value string) {
+		if value != "" {
+			values[key] = value
+		}
+	}
+
+	add("cloud_url", cfg.CloudURL)
+	add("cache_ttl", cfg.CacheTTL)
+	add("cache_swr", cfg.CacheSWR)
+	if cfg.RequestTimeoutSeconds != nil {
+		values["request_timeout_seconds"] = strconv.Itoa(*cfg.RequestTimeoutSeconds)
+	}
+	add("ca_cert", cfg.CACert)
+	add("oidc_issuer", cfg.OIDCIssuer)
+	add("oidc_client_id", cfg.OIDCClientID)
+	add("theme", cfg.Theme.Name)
+	for role, spec := range cfg.Theme.Colors {
+		add("theme."+role, spec)
+	}
+	for _, metric := range thresholdMetrics {
+		levels := thresholdLevels(metric)
+		if levels.Warning > 0 {
+			add("thresholds."+metric+".warning", strconv.FormatFloat(levels.Warning, 'f', -1, 64))
+		}
+		if levels.Critical > 0 {
+			add("thresholds."+metric+".critical", strconv.FormatFloat(levels.Critical, 'f', -1, 64))
+		}
+	}
+	for name, value := range cfg.Ranges {
+		add("ranges."+name, value)
+	}
+	return values
+}
+
+var configExportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Export shareable settings",
+	Long: `Write the non-secret configuration (cloud_url, thresholds, theme,
+ranges, and other settings) to a file, or to stdout when no file is given.
+The token and other credentials are never exported.
+
+The file can be distributed to a team and applied with 'vstats config
+import'.
+
+Examples:
+  vstats config export team.yaml
+  vstats config export > team.yaml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := yaml.Marshal(shareableConfig())
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 || args[0] == "-" {
+			_, err := dataOut.Write(data)
+			return err
+		}
+		if err := os.WriteFile(args[0], data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", args[0], err)
+		}
+		fmt.Printf("✓ Configuration exported to %s\n", args[0])
+		return nil
+	},
+}
+
+var configImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import shared settings",
+	Long: `Merge settings from a file written by 'vstats config export' into
+the local configuration.
+
+Every key is validated as 'vstats config set' would before anything is
+saved; nothing is applied if any key is invalid. Credentials are never
+imported, so an import can't change who you are logged in as. Each
+changed key is reported.
+
+If the file points cloud_url at a different host while a login is stored,
+the import asks first, since the stored token would then be sent to that
+host. Use --yes to accept without prompting.
+
+Examples:
+  vstats config import team.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", args[0], err)
+		}
+		var imported map[string]string
+		if err := yaml.Unmarshal(data, &imported); err != nil {
+			return fmt.Errorf("invalid config file %s: %w", args[0], err)
+		}
+
+		keys := make([]string, 0, len(imported))
+		for key := range imported {
+			if secretConfigKeys[key] {
+				return fmt.Errorf("refusing to import %s: credentials can't be imported", key)
+			}
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		before := shareableConfig()
+		for _, key := range keys {
+			if err := setConfigValue(key, imported[key]); err != nil {
+				// Nothing has been saved yet, so the file on disk is untouched
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+		after := shareableConfig()
+
+		changed := 0
+		for _, key := range keys {
+			if before[key] == after[key] {
+				continue
+			}
+			changed++
+			old := before[key]
+			if old == "" {
+				old = "(unset)"
+			}
+			fmt.Printf("  %s: %s -> %s\n", key, old, after[key])
+		}
+		if changed == 0 {
+			fmt.Println("✓ Configuration already up to date")
+			return nil
+		}
+
+		loadKeyringTokens()
+		if before["cloud_url"] != after["cloud_url"] && cfg.Token != "" {
+			ok, err := confirm(fmt.Sprintf("Your stored login token will be sent to %s. Continue?", after["cloud_url"]))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+		}
+
+		if err := SaveConfig(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ Imported %d setting(s) from %s\n", changed, args[0])
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configExportCmd)
+	configCmd.AddCommand(configImportCmd)
+}
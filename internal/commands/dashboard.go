@@ -0,0 +1,384 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// Dashboard tabs
+const (
+	dashboardServersTab = iota
+	dashboardWebTab
+)
+
+// Keys decoded from the terminal input by dashboardKeys
+const (
+	keyUp   = "up"
+	keyDown = "down"
+	keyTab  = "tab"
+	keyQuit = "quit"
+)
+
+// dashboardDetailLines is the number of rows reserved for the detail pane
+// and the header and footer around the list
+const dashboardDetailLines = 16
+
+// dashboardCmd is an interactive view of servers and web instances
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Interactive view of servers and web instances",
+	Long: `Show a full-screen, interactive view of your servers and web
+instances.
+
+The Servers tab lists every server with a detail pane below showing live
+metrics for the selected one. The Web tab lists web instances. The view
+refreshes every --interval.
+
+Keys:
+  ↑/↓, k/j   move the selection
+  tab, 1, 2  switch between the Servers and Web tabs
+  r          refresh now
+  q          quit (Ctrl-C also works)
+
+Examples:
+  vstats dashboard
+  vstats dashboard --interval 10s`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireLogin(); err != nil {
+			return err
+		}
+
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if interval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+		stdinFd := int(os.Stdin.Fd())
+		if !term.IsTerminal(stdinFd) || !term.IsTerminal(int(os.Stdout.Fd())) {
+			return fmt.Errorf("dashboard needs an interactive terminal; use 'vstats server list' or 'vstats web list' in scripts")
+		}
+		client := NewClient()
+
+		oldState, err := term.MakeRaw(stdinFd)
+		if err != nil {
+			return fmt.Errorf("failed to set terminal raw mode: %w", err)
+		}
+		defer term.Restore(stdinFd, oldState)
+		raw := make(chan byte)
+		go readKeys(raw)
+		keys := dashboardKeys(raw)
+
+		fmt.Print(termAltScreenOn + termHideCursor)
+		defer fmt.Print(termShowCursor + termAltScreenOff)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		view := &dashboardView{metricsCh: make(chan dashboardMetrics)}
+		view.refresh(client)
+		for {
+			view.render()
+
+			select {
+			case <-cmd.Context().Done():
+				return nil
+			case m := <-view.metricsCh:
+				view.applyMetrics(m)
+			case <-ticker.C:
+				view.refresh(client)
+			case key := <-keys:
+				switch key {
+				case keyQuit:
+					return nil
+				case keyUp:
+					view.move(-1, client)
+				case keyDown:
+					view.move(1, client)
+				case keyTab:
+					view.tab = (view.tab + 1) % 2
+				case "1":
+					view.tab = dashboardServersTab
+				case "2":
+					view.tab = dashboardWebTab
+				case "r":
+					view.refresh(client)
+				}
+			}
+		}
+	},
+}
+
+// dashboardKeys decodes raw terminal bytes into key names, turning arrow
+// key escape sequences into keyUp and keyDown
+func dashboardKeys(raw <-chan byte) <-chan string {
+	keys := make(chan string)
+	go func() {
+		var seq []byte
+		for b := range raw {
+			if len(seq) == 1 && b != '[' {
+				// A lone Escape; treat the byte after it as a normal key
+				seq = nil
+			}
+			if len(seq) > 0 || b == 0x1b {
+				seq = append(seq, b)
+				if len(seq) == 3 {
+					switch b {
+					case 'A':
+						keys <- keyUp
+					case 'B':
+						keys <- keyDown
+					}
+					seq = nil
+				}
+				continue
+			}
+
+			switch b {
+			case 'q', 3: // 3 is Ctrl-C in raw mode
+				keys <- keyQuit
+			case 'k':
+				keys <- keyUp
+			case 'j':
+				keys <- keyDown
+			case '\t':
+				keys <- keyTab
+			default:
+				keys <- string(b)
+			}
+		}
+	}()
+	return keys
+}
+
+// dashboardMetrics is the result of a background metrics fetch. seq
+// identifies the request, so responses for an earlier selection are dropped.
+type dashboardMetrics struct {
+	seq     int
+	metrics *ServerMetrics
+	err     error
+}
+
+// dashboardView holds the state of the dashboard between refreshes
+type dashboardView struct {
+	tab       int
+	servers   []Server
+	instances []WebInstance
+	selected  [2]int
+	metrics   *ServerMetrics
+	loading   bool
+	err       error
+	updated   time.Time
+
+	// metricsSeq numbers metrics requests; results arrive on metricsCh
+	metricsSeq int
+	metricsCh  chan dashboardMetrics
+}
+
+// refresh fetches servers, web instances, and the selected server's metrics
+func (v *dashboardView) refresh(client *Client) {
+	servers, err := client.ListServers()
+	if err != nil {
+		v.err = err
+		return
+	}
+	sortServers(servers, "name", false)
+	v.servers, v.err = servers, nil
+	v.updated = time.Now()
+
+	// Keep showing the last web list if it can't be refreshed
+	if instances, err := client.ListWebInstances(); err == nil {
+		v.instances = instances
+	} else {
+		v.err = err
+	}
+	v.clampSelection()
+	v.refreshMetrics(client)
+}
+
+// refreshMetrics starts fetching live metrics for the selected server in
+// the background, so moving the selection never waits on the network
+func (v *dashboardView) refreshMetrics(client *Client) {
+	v.metricsSeq++
+	v.metrics, v.loading = nil, false
+	if len(v.servers) == 0 {
+		return
+	}
+	v.loading = true
+	seq, id, results := v.metricsSeq, v.servers[v.selected[dashboardServersTab]].ID, v.metricsCh
+	go func() {
+		resp, err := client.GetServerMetrics(id)
+		m := dashboardMetrics{seq: seq, err: err}
+		if err == nil {
+			m.metrics = resp.Metrics
+		}
+		select {
+		case results <- m:
+		case <-commandContext().Done():
+		}
+	}()
+}
+
+// applyMetrics shows a metrics result unless the selection has moved on
+func (v *dashboardView) applyMetrics(m dashboardMetrics) {
+	if m.seq != v.metricsSeq {
+		return
+	}
+	v.loading = false
+	if m.err != nil {
+		v.err = m.err
+		return
+	}
+	v.metrics = m.metrics
+}
+
+// move shifts the selection in the current tab by delta rows
+func (v *dashboardView) move(delta int, client *Client) {
+	v.selected[v.tab] += delta
+	v.clampSelection()
+	if v.tab == dashboardServersTab {
+		v.refreshMetrics(client)
+	}
+}
+
+// clampSelection keeps each tab's selection within its list
+func (v *dashboardView) clampSelection() {
+	counts := [2]int{len(v.servers), len(v.instances)}
+	for tab, count := range counts {
+		if v.selected[tab] >= count {
+			v.selected[tab] = count - 1
+		}
+		if v.selected[tab] < 0 {
+			v.selected[tab] = 0
+		}
+	}
+}
+
+// render draws the screen
+func (v *dashboardView) render() {
+	height := 24
+	if _, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+		height = h
+	}
+	listRows := max(height-dashboardDetailLines, 3)
+
+	var buf bytes.Buffer
+	tabs := []string{"1 Servers", "2 Web"}
+	for i, name := range tabs {
+		if i == v.tab {
+			tabs[i] = color(themeColor(RoleHeader), "["+name+"]")
+		} else {
+			tabs[i] = " " + name + " "
+		}
+	}
+	fmt.Fprintf(&buf, "vStats dashboard  %s  - updated %s\n\n", strings.Join(tabs, " "), v.updated.Format("15:04:05"))
+
+	if v.tab == dashboardServersTab {
+		v.renderServers(&buf, listRows)
+	} else {
+		v.renderWeb(&buf, listRows)
+	}
+
+	if v.err != nil {
+		fmt.Fprintf(&buf, "\n%s\n", color(themeColor(RoleCritical), "Refresh failed: "+v.err.Error()))
+	}
+	fmt.Fprintf(&buf, "\n%s\n", color(ColorGray, "↑/↓:select  tab:switch  r:refresh  q:quit"))
+
+	// Raw mode disables newline translation, so emit explicit carriage returns
+	fmt.Print(termClearScreen + strings.ReplaceAll(buf.String(), "\n", "\r\n"))
+}
+
+// renderServers draws the server list and the detail pane
+func (v *dashboardView) renderServers(buf *bytes.Buffer, rows int) {
+	if len(v.servers) == 0 {
+		fmt.Fprintln(buf, "No servers found.")
+		return
+	}
+
+	selected := v.selected[dashboardServersTab]
+	first, last := dashboardWindow(selected, len(v.servers), rows)
+	table := NewTable("", "NAME", "STATUS", "CPU", "MEM", "DISK", "LAST SEEN")
+	table.Writer = buf
+	for i := first; i < last; i++ {
+		s := v.servers[i]
+		table.AddRow(dashboardMarker(i == selected), s.Name, formatStatus(s.Status),
+			topMetricCell(s, "cpu"), topMetricCell(s, "mem"), topMetricCell(s, "disk"),
+			formatTimestamp(s.LastSeenAt))
+	}
+	table.Render()
+	fmt.Fprintf(buf, "%s\n", color(ColorGray, fmt.Sprintf("%d–%d of %d", first+1, last, len(v.servers))))
+
+	s := v.servers[selected]
+	fmt.Fprintf(buf, "\n%s\n", color(themeColor(RoleHeader), s.Name+" ("+ptrString(s.IPAddress)+")"))
+	m := v.metrics
+	if m == nil {
+		if v.loading {
+			fmt.Fprintln(buf, "  Loading metrics...")
+		} else {
+			fmt.Fprintln(buf, "  No metrics")
+		}
+		return
+	}
+	fmt.Fprintf(buf, "  CPU:        %s   Load: %s / %s / %s\n", ptrFloat(m.CPUUsage),
+		ptrFloatRaw(m.LoadAvg1), ptrFloatRaw(m.LoadAvg5), ptrFloatRaw(m.LoadAvg15))
+	if m.CPUTempC != nil {
+		fmt.Fprintf(buf, "  CPU Temp:   %s\n", formatTemperature(m.CPUTempC))
+	}
+	fmt.Fprintf(buf, "  Memory:     %s / %s\n", ptrBytes(m.MemoryUsed), ptrBytes(m.MemoryTotal))
+	fmt.Fprintf(buf, "  Disk:       %s / %s\n", ptrBytes(m.DiskUsed), ptrBytes(m.DiskTotal))
+	fmt.Fprintf(buf, "  Processes:  %s\n", ptrInt(m.ProcessCount))
+	if m.NetRxBytes != nil || m.NetTxBytes != nil {
+		fmt.Fprintf(buf, "  Network:    rx %s / tx %s\n", formatRate(m.NetRxBytes), formatRate(m.NetTxBytes))
+	}
+}
+
+// renderWeb draws the web instance list
+func (v *dashboardView) renderWeb(buf *bytes.Buffer, rows int) {
+	if len(v.instances) == 0 {
+		fmt.Fprintln(buf, "No web instances deployed yet.")
+		return
+	}
+
+	selected := v.selected[dashboardWebTab]
+	first, last := dashboardWindow(selected, len(v.instances), rows)
+	table := NewTable("", "NAME", "STATUS", "URL", "CREATED")
+	table.Writer = buf
+	for i := first; i < last; i++ {
+		w := v.instances[i]
+		table.AddRow(dashboardMarker(i == selected), w.Name, formatWebStatus(w.Status), w.URL, formatTimestamp(&w.CreatedAt))
+	}
+	table.Render()
+	fmt.Fprintf(buf, "%s\n", color(ColorGray, fmt.Sprintf("%d–%d of %d", first+1, last, len(v.instances))))
+}
+
+// dashboardWindow returns the range of rows to show so that selected stays
+// visible in a list of count rows with room for rows
+func dashboardWindow(selected, count, rows int) (first, last int) {
+	if count <= rows {
+		return 0, count
+	}
+	first = max(selected-rows/2, 0)
+	if first+rows > count {
+		first = count - rows
+	}
+	return first, first + rows
+}
+
+// dashboardMarker marks the selected row
+func dashboardMarker(selected bool) string {
+	if selected {
+		return color(themeColor(RoleHeader), "›")
+	}
+	return " "
+}
+
+func init() {
+	rootCmd.AddCommand(dashboardCmd)
+
+	dashboardCmd.Flags().Duration("interval", 5*time.Second, "refresh interval")
+}
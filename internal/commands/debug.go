@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Debug levels for --debug
+const (
+	debugRequests = 1 // method, URL, status, and duration
+	debugBodies   = 2 // also request and response bodies
+)
+
+// debugBodyLimit caps how much of a body is dumped at --debug=2
+const debugBodyLimit = 64 * 1024
+
+// debugf writes one debug line to the client's logger
+func (c *Client) debugf(format string, args ...interface{}) {
+	if c.Logger == nil {
+		return
+	}
+	fmt.Fprintf(c.Logger, "[debug] "+format+"\n", args...)
+}
+
+// debugRequest logs an outgoing request, including its headers and body at
+// --debug=2 with the Authorization header and secret fields redacted
+func (c *Client) debugRequest(req *http.Request) {
+	if c.Debug < debugRequests {
+		return
+	}
+	c.debugf("--> %s %s", req.Method, req.URL)
+	if c.Debug < debugBodies {
+		return
+	}
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := strings.Join(req.Header[name], ", ")
+		if strings.EqualFold(name, "Authorization") {
+			value = "[REDACTED]"
+		}
+		c.debugf("    %s: %s", name, value)
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			c.debugBody(data)
+		}
+	}
+}
+
+// debugResponse logs a response status and duration, and the body at
+// --debug=2 when it has been read
+func (c *Client) debugResponse(req *http.Request, resp *http.Response, err error, start time.Time, body []byte) {
+	if c.Debug < debugRequests {
+		return
+	}
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		c.debugf("<-- %s %s failed after %s: %v", req.Method, req.URL, elapsed, err)
+		return
+	}
+	c.debugf("<-- %d %s %s (%s)", resp.StatusCode, req.Method, req.URL, elapsed)
+	if c.Debug >= debugBodies && body != nil {
+		c.debugBody(body)
+	}
+}
+
+// debugSecretFields are JSON fields whose values are never dumped: tokens
+// and codes from the login flows and agent keys from server endpoints
+var debugSecretFields = map[string]bool{
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"id_token":      true,
+	"code":          true,
+	"code_verifier": true,
+	"device_code":   true,
+	"agent_key":     true,
+	"client_secret": true,
+	"password":      true,
+}
+
+// redactBody replaces the values of secret fields anywhere in a JSON body.
+// Bodies that aren't JSON are returned unchanged.
+func redactBody(data []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return data
+	}
+	redacted, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return data
+	}
+	return redacted
+}
+
+// redactValue walks a decoded JSON value, redacting secret fields
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if debugSecretFields[strings.ToLower(key)] {
+				if s, ok := value.(string); !ok || s != "" {
+					v[key] = "[REDACTED]"
+				}
+				continue
+			}
+			v[key] = redactValue(value)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+	}
+	return v
+}
+
+// debugBody dumps a body with secret fields redacted, truncated to
+// debugBodyLimit
+func (c *Client) debugBody(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	data = redactBody(data)
+	if len(data) > debugBodyLimit {
+		c.debugf("    %s... (%d bytes truncated)", bytes.TrimSpace(data[:debugBodyLimit]), len(data)-debugBodyLimit)
+		return
+	}
+	c.debugf("    %s", bytes.TrimSpace(data))
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugLogRedactsSecrets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"resp-token","refresh_token":"resp-refresh","server":{"name":"web-01","agent_key":"resp-agent-key"}}`))
+	}))
+	defer srv.Close()
+
+	var log bytes.Buffer
+	client := &Client{
+		BaseURL:    srv.URL,
+		Token:      "bearer-secret",
+		HTTPClient: srv.Client(),
+		Debug:      debugBodies,
+		Logger:     &log,
+	}
+	body := map[string]string{"code": "auth-code", "code_verifier": "verifier-secret", "client_id": "vstats-cli"}
+	if err := client.Do("POST", "/api/auth/oidc/token", body, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	out := log.String()
+	for _, secret := range []string{"bearer-secret", "auth-code", "verifier-secret", "resp-token", "resp-refresh", "resp-agent-key"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("debug log contains %q:\n%s", secret, out)
+		}
+	}
+	for _, want := range []string{"--> POST " + srv.URL + "/api/auth/oidc/token", "<-- 200 POST", `"client_id":"vstats-cli"`, `"name":"web-01"`, "[REDACTED]"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("debug log is missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// agentDeployment is the outcome of deploying the agent to one host
+type agentDeployment struct {
+	Host   string
+	Server *Server
+	Err    error
+}
+
+// readHostsFile reads one host per line, skipping blank lines and # comments
+func readHostsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hosts file: %w", err)
+	}
+	defer f.Close()
+
+	var hosts []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		hosts = append(hosts, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read hosts file: %w", err)
+	}
+	return hosts, nil
+}
+
+// deployAgents deploys the agent to many hosts concurrently, creating one
+// server per host, and prints a summary. One host failing doesn't stop the
+// others; an error is returned if any of them failed. With wait set it also
+// waits up to waitTimeout (0 = forever) for each agent to check in.
+func deployAgents(client *Client, hosts []string, parallel int, wait bool, waitTimeout time.Duration) error {
+	if !quiet {
+		fmt.Printf("Deploying vStats agent to %d hosts (%d at a time)...\n\n", len(hosts), parallel)
+	}
+
+	var mu sync.Mutex
+	results := make([]agentDeployment, len(hosts))
+	forEachParallel(len(hosts), parallel, func(i int) {
+		out := &prefixWriter{w: os.Stdout, prefix: "[" + hosts[i] + "] ", mu: &mu}
+		results[i] = deployAgent(client, hosts[i], out, wait, waitTimeout)
+		out.Flush()
+	})
+	invalidateServerCache()
+
+	fmt.Println()
+	failed := 0
+	table := NewTable("HOST", "SERVER ID", "RESULT")
+	for _, r := range results {
+		serverID := "-"
+		if r.Server != nil {
+			serverID = r.Server.ID
+		}
+		result := color(themeColor(RoleOnline), "✓ deployed")
+		if r.Err != nil {
+			failed++
+			result = color(themeColor(RoleCritical), "✗ "+r.Err.Error())
+		}
+		table.AddRow(r.Host, serverID, result)
+	}
+	table.Render()
+	fmt.Println()
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d deployments failed", failed, len(results))
+	}
+	if !quiet {
+		fmt.Printf("✓ Agent deployed to all %d hosts\n", len(results))
+	}
+	return nil
+}
+
+// deployAgent creates a server named after the host and installs the agent
+// on it non-interactively, writing ssh output to out
+func deployAgent(client *Client, hostArg string, out io.Writer, wait bool, waitTimeout time.Duration) agentDeployment {
+	result := agentDeployment{Host: hostArg}
+	user, host := resolveSSHTarget(hostArg)
+
+	// Batch mode keeps a password prompt from blocking the whole run
+	sshArgs := append([]string{"-o", "BatchMode=yes"}, buildSSHArgs(user, host)...)
+	if err := sshPreflight(sshArgs); err != nil {
+		result.Err = err
+		return result
+	}
+
+	server, err := client.CreateServer(host)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to create server: %w", err)
+		return result
+	}
+	result.Server = server
+	fmt.Fprintf(out, "✓ Server created: %s\n", server.ID)
+
+	if err := runSSHCommandIO(sshArgs, agentInstallCommand(host), nil, out, out); err != nil {
+		result.Err = err
+		return result
+	}
+
+	if wait {
+		fmt.Fprintln(out, "Waiting for the agent to connect...")
+		if err := waitForAgent(client, server.ID, server.Name, waitTimeout, false); err != nil {
+			result.Err = err
+			return result
+		}
+		fmt.Fprintln(out, "✓ Agent connected")
+	}
+	return result
+}
+
+// prefixWriter writes complete lines to w with a prefix, serializing
+// writes from concurrent deployments through a shared mutex
+type prefixWriter struct {
+	w      io.Writer
+	prefix string
+	mu     *sync.Mutex
+	buf    bytes.Buffer
+}
+
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.buf.Write(data)
+	for {
+		line, err := p.buf.ReadBytes('\n')
+		if err != nil {
+			// Keep the partial line until the rest arrives
+			p.buf.Write(line)
+			return len(data), nil
+		}
+		p.writeLine(line)
+	}
+}
+
+// Flush writes any trailing partial line
+func (p *prefixWriter) Flush() {
+	if p.buf.Len() > 0 {
+		p.writeLine(append(p.buf.Bytes(), '\n'))
+		p.buf.Reset()
+	}
+}
+
+func (p *prefixWriter) writeLine(line []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintf(p.w, "%s%s", p.prefix, line)
+}
@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Device flow error codes returned by the token endpoint (RFC 8628)
+const (
+	deviceAuthorizationPending = "authorization_pending"
+	deviceSlowDown             = "slow_down"
+	deviceAccessDenied         = "access_denied"
+	deviceExpiredToken         = "expired_token"
+)
+
+// Polling defaults used when the device endpoint doesn't specify them
+const (
+	defaultDevicePollInterval = 5 * time.Second
+	defaultDeviceCodeLifetime = 15 * time.Minute
+)
+
+// DeviceCodeResponse starts a device login: the user enters UserCode at
+// VerificationURI while the CLI polls with DeviceCode
+type DeviceCodeResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int    `json:"expires_in,omitempty"`
+	Interval                int    `json:"interval,omitempty"`
+}
+
+// DeviceTokenResponse is the token issued once a device login is approved.
+// ExpiresIn is the token lifetime in seconds.
+type DeviceTokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"`
+}
+
+// StartDeviceLogin requests a device and user code
+func (c *Client) StartDeviceLogin() (*DeviceCodeResponse, error) {
+	var resp DeviceCodeResponse
+	if err := c.Do("POST", "/api/auth/device", map[string]string{"client_id": oidcDefaultClientID}, &resp); err != nil {
+		return nil, err
+	}
+	if resp.DeviceCode == "" || resp.UserCode == "" || resp.VerificationURI == "" {
+		return nil, fmt.Errorf("device endpoint returned an incomplete response")
+	}
+	return &resp, nil
+}
+
+// PollDeviceToken asks whether a device login has been approved. While it
+// is pending the error is a *ResponseError with Code authorization_pending.
+func (c *Client) PollDeviceToken(deviceCode string) (*DeviceTokenResponse, error) {
+	var resp DeviceTokenResponse
+	if err := c.Do("POST", "/api/auth/device/token", map[string]string{"device_code": deviceCode}, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Token == "" {
+		return nil, fmt.Errorf("token endpoint returned no token")
+	}
+	return &resp, nil
+}
+
+// runDeviceLogin performs the device authorization flow: it prints the
+// user code and verification URL, opens the browser, and polls until the
+// login is approved, denied, or the code expires
+func runDeviceLogin() (*DeviceTokenResponse, error) {
+	client := newClientFor(currentCloudURL(), "")
+	code, err := client.StartDeviceLogin()
+	if err != nil {
+		return nil, err
+	}
+
+	interval := defaultDevicePollInterval
+	if code.Interval > 0 {
+		interval = time.Duration(code.Interval) * time.Second
+	}
+	lifetime := defaultDeviceCodeLifetime
+	if code.ExpiresIn > 0 {
+		lifetime = time.Duration(code.ExpiresIn) * time.Second
+	}
+
+	target := code.VerificationURI
+	if code.VerificationURIComplete != "" {
+		target = code.VerificationURIComplete
+	}
+	fmt.Println("To sign in, open this URL in a browser:")
+	fmt.Println()
+	fmt.Println("  " + code.VerificationURI)
+	fmt.Println()
+	fmt.Printf("and enter the code: %s\n", code.UserCode)
+	fmt.Println()
+	_ = openBrowser(target)
+	fmt.Println("Waiting for approval...")
+
+	deadline := time.After(lifetime)
+	for {
+		select {
+		case <-time.After(interval):
+		case <-deadline:
+			return nil, errors.New("the code expired before the login was approved")
+		case <-commandContext().Done():
+			return nil, commandContext().Err()
+		}
+
+		resp, err := client.PollDeviceToken(code.DeviceCode)
+		if err == nil {
+			return resp, nil
+		}
+		var respErr *ResponseError
+		if !errors.As(err, &respErr) {
+			return nil, err
+		}
+		switch respErr.Code {
+		case deviceAuthorizationPending:
+		case deviceSlowDown:
+			interval += 5 * time.Second
+		case deviceAccessDenied:
+			return nil, errors.New("the login was denied")
+		case deviceExpiredToken:
+			return nil, errors.New("the code expired before the login was approved")
+		default:
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dnsLookupTimeout bounds each reverse DNS lookup
+const dnsLookupTimeout = 2 * time.Second
+
+// resolvePTRs reverse-resolves each unique IP concurrently and returns a map
+// from IP to hostname. Unresolvable IPs are left out of the map.
+func resolvePTRs(ips []string) map[string]string {
+	unique := make([]string, 0, len(ips))
+	seen := make(map[string]bool)
+	for _, ip := range ips {
+		if ip != "" && !seen[ip] {
+			seen[ip] = true
+			unique = append(unique, ip)
+		}
+	}
+
+	var mu sync.Mutex
+	names := make(map[string]string)
+	forEachParallel(len(unique), concurrency, func(i int) {
+		ctx, cancel := context.WithTimeout(context.Background(), dnsLookupTimeout)
+		defer cancel()
+
+		hosts, err := net.DefaultResolver.LookupAddr(ctx, unique[i])
+		if err != nil || len(hosts) == 0 {
+			return
+		}
+		mu.Lock()
+		names[unique[i]] = strings.TrimSuffix(hosts[0], ".")
+		mu.Unlock()
+	})
+	return names
+}
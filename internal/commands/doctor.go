@@ -0,0 +1,256 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Permissions the config directory and file should have
+const (
+	configDirMode  os.FileMode = 0700
+	configFileMode os.FileMode = 0600
+)
+
+// doctorResult is the outcome of one doctor check. Fix is set when the
+// problem can be repaired automatically; it returns a log line describing
+// what changed and how to undo it.
+type doctorResult struct {
+	Name    string
+	OK      bool
+	Detail  string
+	Fix     func() (string, error)
+	FixHint string
+}
+
+// doctorCmd diagnoses (and optionally repairs) the local CLI setup
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the CLI setup for problems",
+	Long: `Check the local configuration, credentials, and connectivity to
+vStats Cloud.
+
+With --fix, safe repairs are applied automatically and each change is
+logged along with how to revert it:
+  - create the config directory with 0700 permissions
+  - restrict the config file to 0600
+  - strip a trailing slash from cloud_url
+  - offer to log in again when the token has expired
+
+Examples:
+  vstats doctor
+  vstats doctor --fix`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fix, _ := cmd.Flags().GetBool("fix")
+
+		problems := 0
+		for _, check := range []func() doctorResult{
+			checkConfigDir,
+			checkConfigFile,
+			checkCloudURL,
+			func() doctorResult { return checkLogin(cmd) },
+			checkConnectivity,
+		} {
+			result := check()
+			if result.OK {
+				fmt.Printf("%s %s: %s\n", color(themeColor(RoleOnline), "✓"), result.Name, result.Detail)
+				continue
+			}
+
+			fmt.Printf("%s %s: %s\n", color(themeColor(RoleCritical), "✗"), result.Name, result.Detail)
+			switch {
+			case fix && result.Fix != nil:
+				change, err := result.Fix()
+				if err != nil {
+					fmt.Printf("  fix failed: %v\n", err)
+					problems++
+				} else {
+					fmt.Printf("  fixed: %s\n", change)
+				}
+			case result.Fix != nil:
+				fmt.Println("  can be fixed with --fix")
+				problems++
+			default:
+				if result.FixHint != "" {
+					fmt.Printf("  %s\n", result.FixHint)
+				}
+				problems++
+			}
+		}
+
+		fmt.Println()
+		if problems > 0 {
+			return fmt.Errorf("%d problem(s) found", problems)
+		}
+		fmt.Println("✓ No problems found")
+		return nil
+	},
+}
+
+// doctorConfigPath returns the config file in use
+func doctorConfigPath() (string, error) {
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+	return GetConfigPath()
+}
+
+// checkPermissions reports whether a file mode grants access beyond want.
+// Windows doesn't use Unix permission bits, so it always passes there.
+func checkPermissions(mode, want os.FileMode) bool {
+	return runtime.GOOS == "windows" || mode.Perm()&^want == 0
+}
+
+// checkConfigDir checks that the config directory exists and is private
+func checkConfigDir() doctorResult {
+	result := doctorResult{Name: "Config directory"}
+	path, err := doctorConfigPath()
+	if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+	dir := filepath.Dir(path)
+
+	info, err := os.Stat(dir)
+	switch {
+	case os.IsNotExist(err):
+		result.Detail = dir + " does not exist"
+		result.Fix = func() (string, error) {
+			if err := os.MkdirAll(dir, configDirMode); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("created %s with mode %04o (undo: remove the directory)", dir, configDirMode), nil
+		}
+	case err != nil:
+		result.Detail = err.Error()
+	case !checkPermissions(info.Mode(), configDirMode):
+		old := info.Mode().Perm()
+		result.Detail = fmt.Sprintf("%s has mode %04o, expected %04o", dir, old, configDirMode)
+		result.Fix = func() (string, error) {
+			if err := os.Chmod(dir, configDirMode); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("changed %s mode %04o -> %04o (undo: chmod %o %s)", dir, old, configDirMode, old, dir), nil
+		}
+	default:
+		result.OK = true
+		result.Detail = dir
+	}
+	return result
+}
+
+// checkConfigFile checks that the config file, which holds the token, is
+// only readable by its owner
+func checkConfigFile() doctorResult {
+	result := doctorResult{Name: "Config file"}
+	path, err := doctorConfigPath()
+	if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		result.OK = true
+		result.Detail = path + " (not created yet)"
+	case err != nil:
+		result.Detail = err.Error()
+	case !checkPermissions(info.Mode(), configFileMode):
+		old := info.Mode().Perm()
+		result.Detail = fmt.Sprintf("%s has mode %04o, expected %04o", path, old, configFileMode)
+		result.Fix = func() (string, error) {
+			if err := os.Chmod(path, configFileMode); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("changed %s mode %04o -> %04o (undo: chmod %o %s)", path, old, configFileMode, old, path), nil
+		}
+	default:
+		result.OK = true
+		result.Detail = path
+	}
+	return result
+}
+
+// checkCloudURL checks cloud_url for a trailing slash, which produces
+// double slashes in API paths
+func checkCloudURL() doctorResult {
+	result := doctorResult{Name: "Cloud URL"}
+	if !strings.HasSuffix(cfg.CloudURL, "/") {
+		result.OK = true
+		result.Detail = cfg.CloudURL
+		return result
+	}
+
+	old := cfg.CloudURL
+	result.Detail = old + " has a trailing slash"
+	result.Fix = func() (string, error) {
+		cfg.CloudURL = strings.TrimRight(old, "/")
+		if err := SaveConfig(); err != nil {
+			cfg.CloudURL = old
+			return "", fmt.Errorf("failed to save config: %w", err)
+		}
+		// config set would strip the slash again, so undoing means editing
+		// the file by hand
+		path, err := GetConfigPath()
+		if err != nil {
+			return fmt.Sprintf("cloud_url %s -> %s", old, cfg.CloudURL), nil
+		}
+		return fmt.Sprintf("cloud_url %s -> %s (undo: set cloud_url back to %s in %s)", old, cfg.CloudURL, old, path), nil
+	}
+	return result
+}
+
+// checkLogin checks that a token is stored and hasn't expired
+func checkLogin(cmd *cobra.Command) doctorResult {
+	result := doctorResult{Name: "Login"}
+	switch {
+	case !IsLoggedIn():
+		result.Detail = "not logged in"
+		result.FixHint = "run 'vstats login'"
+	case cfg.ExpiresAt != 0 && time.Now().Unix() > cfg.ExpiresAt:
+		expired := time.Unix(cfg.ExpiresAt, 0)
+		result.Detail = fmt.Sprintf("token for %s expired %s", cfg.Username, formatTimeAgo(&expired))
+		result.Fix = func() (string, error) {
+			ok, err := confirm("Your token has expired. Log in again now?")
+			if err != nil {
+				return "", err
+			}
+			if !ok {
+				return "", fmt.Errorf("skipped; run 'vstats login' to log in again")
+			}
+			if err := runLogin(cmd, nil); err != nil {
+				return "", err
+			}
+			return "logged in again as " + cfg.Username, nil
+		}
+	default:
+		result.OK = true
+		result.Detail = "logged in as " + cfg.Username
+	}
+	return result
+}
+
+// checkConnectivity checks that vStats Cloud is reachable
+func checkConnectivity() doctorResult {
+	result := doctorResult{Name: "Connectivity"}
+	if err := newClientFor(currentCloudURL(), currentToken()).Probe(); err != nil {
+		result.Detail = fmt.Sprintf("cannot reach %s: %v", currentCloudURL(), err)
+		result.FixHint = "check your network connection and cloud_url"
+		return result
+	}
+	result.OK = true
+	result.Detail = currentCloudURL() + " is reachable"
+	return result
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().Bool("fix", false, "automatically repair problems that are safe to fix")
+}
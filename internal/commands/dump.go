@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// commandDump describes a command for __dump-commands
+type commandDump struct {
+	Name     string        `json:"name"`
+	Path     string        `json:"path"`
+	Use      string        `json:"use"`
+	Short    string        `json:"short,omitempty"`
+	Long     string        `json:"long,omitempty"`
+	Aliases  []string      `json:"aliases,omitempty"`
+	Flags    []flagDump    `json:"flags,omitempty"`
+	Commands []commandDump `json:"commands,omitempty"`
+}
+
+// flagDump describes a flag for __dump-commands
+type flagDump struct {
+	Name       string `json:"name"`
+	Shorthand  string `json:"shorthand,omitempty"`
+	Type       string `json:"type"`
+	Default    string `json:"default"`
+	Usage      string `json:"usage"`
+	Persistent bool   `json:"persistent,omitempty"`
+}
+
+// dumpCommandsCmd prints the command tree as JSON for docs and tooling
+var dumpCommandsCmd = &cobra.Command{
+	Use:    "__dump-commands",
+	Short:  "Print every command and flag as JSON",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return OutputJSON(dumpCommand(rootCmd))
+	},
+}
+
+// dumpCommand describes a command and its visible subcommands, listing
+// only the flags the command defines itself (persistent ones included)
+func dumpCommand(cmd *cobra.Command) commandDump {
+	d := commandDump{
+		Name:    cmd.Name(),
+		Path:    cmd.CommandPath(),
+		Use:     cmd.Use,
+		Short:   cmd.Short,
+		Long:    cmd.Long,
+		Aliases: cmd.Aliases,
+	}
+
+	persistent := cmd.PersistentFlags()
+	cmd.NonInheritedFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		d.Flags = append(d.Flags, flagDump{
+			Name:       f.Name,
+			Shorthand:  f.Shorthand,
+			Type:       f.Value.Type(),
+			Default:    f.DefValue,
+			Usage:      f.Usage,
+			Persistent: persistent.Lookup(f.Name) != nil,
+		})
+	})
+
+	for _, sub := range cmd.Commands() {
+		if sub.Hidden {
+			continue
+		}
+		d.Commands = append(d.Commands, dumpCommand(sub))
+	}
+	return d
+}
+
+func init() {
+	rootCmd.AddCommand(dumpCommandsCmd)
+}
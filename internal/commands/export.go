@@ -0,0 +1,291 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// exportFormatVersion is the current version of the server export format
+const exportFormatVersion = 1
+
+// ServerExport is a portable snapshot of the servers in an account
+type ServerExport struct {
+	Version    int              `json:"version" yaml:"version"`
+	ExportedAt time.Time        `json:"exported_at" yaml:"exported_at"`
+	CloudURL   string           `json:"cloud_url" yaml:"cloud_url"`
+	Servers    []ExportedServer `json:"servers" yaml:"servers"`
+}
+
+// ExportedServer is a single server entry in an export
+type ExportedServer struct {
+	ID           string     `json:"id" yaml:"id"`
+	Name         string     `json:"name" yaml:"name"`
+	Hostname     *string    `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	IPAddress    *string    `json:"ip_address,omitempty" yaml:"ip_address,omitempty"`
+	OSType       *string    `json:"os_type,omitempty" yaml:"os_type,omitempty"`
+	OSVersion    *string    `json:"os_version,omitempty" yaml:"os_version,omitempty"`
+	AgentVersion *string    `json:"agent_version,omitempty" yaml:"agent_version,omitempty"`
+	Status       string     `json:"status" yaml:"status"`
+	CreatedAt    time.Time  `json:"created_at" yaml:"created_at"`
+	LastSeenAt   *time.Time `json:"last_seen_at,omitempty" yaml:"last_seen_at,omitempty"`
+	RemovedAt    *time.Time `json:"removed_at,omitempty" yaml:"removed_at,omitempty"`
+
+	Tags     map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	AgentKey string            `json:"agent_key,omitempty" yaml:"agent_key,omitempty"`
+	Metrics  *ServerMetrics    `json:"metrics,omitempty" yaml:"metrics,omitempty"`
+}
+
+// serverExportCmd exports all servers to a file
+var serverExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export all servers to a file",
+	Long: `Export a snapshot of all servers in your account.
+
+The export is written as JSON, or YAML when the file name ends in .yaml or
+.yml (or -o yaml is used when writing to stdout).
+
+Agent keys are left out unless --include-keys is given; an export with
+keys is a secret and is written with owner-only permissions. Use
+--include-metrics to also record each server's current metrics.
+
+With --append, the servers are merged into an existing export file:
+entries are updated by ID, new servers are added, and servers that no
+longer exist are kept with a removed_at timestamp, so the file becomes a
+running record of the fleet. Agent keys already in the file are kept even
+without --include-keys; pass --strip-keys to remove them.
+
+Examples:
+  vstats server export --file servers.json
+  vstats server export --file backup.json --include-keys --include-metrics
+  vstats server export --file inventory.yaml --append`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireLogin(); err != nil {
+			return err
+		}
+
+		file, _ := cmd.Flags().GetString("file")
+		appendMode, _ := cmd.Flags().GetBool("append")
+		if appendMode && file == "" {
+			return fmt.Errorf("--append requires --file")
+		}
+		includeKeys, _ := cmd.Flags().GetBool("include-keys")
+		stripKeys, _ := cmd.Flags().GetBool("strip-keys")
+		if stripKeys && !appendMode {
+			return fmt.Errorf("--strip-keys only applies with --append")
+		}
+		if stripKeys && includeKeys {
+			return fmt.Errorf("--strip-keys and --include-keys can't be used together")
+		}
+
+		client := NewClient()
+		servers, err := client.ListServers()
+		if err != nil {
+			return fmt.Errorf("failed to list servers: %w", err)
+		}
+
+		export := newServerExport(servers, includeKeys)
+		if includeMetrics, _ := cmd.Flags().GetBool("include-metrics"); includeMetrics {
+			addExportMetrics(client, export)
+		}
+		if appendMode {
+			existing, err := readServerExport(file)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				added, updated, removed := mergeServerExport(existing, export, stripKeys)
+				export = existing
+				fmt.Fprintf(os.Stderr, "Merged: %d added, %d updated, %d marked removed\n", added, updated, removed)
+			}
+		}
+
+		if file == "" {
+			switch outputFmt {
+			case "yaml":
+				return OutputYAML(export)
+			case "template":
+				return OutputTemplate(export)
+			default:
+				return OutputJSON(export)
+			}
+		}
+
+		if err := writeServerExport(file, export); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+		fmt.Printf("✓ Exported %d servers to %s\n", len(servers), file)
+		return nil
+	},
+}
+
+// newServerExport builds an export from the current server list, with
+// agent keys only when includeKeys is set
+func newServerExport(servers []Server, includeKeys bool) *ServerExport {
+	export := &ServerExport{
+		Version:    exportFormatVersion,
+		ExportedAt: time.Now().UTC(),
+		CloudURL:   currentCloudURL(),
+		Servers:    make([]ExportedServer, 0, len(servers)),
+	}
+	for _, s := range servers {
+		export.Servers = append(export.Servers, ExportedServer{
+			ID:           s.ID,
+			Name:         s.Name,
+			Hostname:     s.Hostname,
+			IPAddress:    s.IPAddress,
+			OSType:       s.OSType,
+			OSVersion:    s.OSVersion,
+			AgentVersion: s.AgentVersion,
+			Status:       s.Status,
+			CreatedAt:    s.CreatedAt,
+			LastSeenAt:   s.LastSeenAt,
+			Tags:         s.Tags,
+		})
+		if includeKeys {
+			export.Servers[len(export.Servers)-1].AgentKey = s.AgentKey
+		}
+	}
+	return export
+}
+
+// addExportMetrics fetches the current metrics of every exported server in
+// parallel. A server whose metrics can't be fetched is exported without
+// them, with a warning.
+func addExportMetrics(client *Client, export *ServerExport) {
+	forEachParallel(len(export.Servers), concurrency, func(i int) {
+		s := &export.Servers[i]
+		resp, err := client.GetServerMetrics(s.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: no metrics for '%s': %v\n", s.Name, err)
+			return
+		}
+		s.Metrics = resp.Metrics
+	})
+}
+
+// mergeServerExport merges a fresh export into an existing one in place,
+// updating entries by ID and marking missing servers as removed. An agent
+// key already in the file survives an update without one, unless
+// stripKeys is set, which clears every key in the file.
+func mergeServerExport(existing, current *ServerExport, stripKeys bool) (added, updated, removed int) {
+	present := make(map[string]ExportedServer, len(current.Servers))
+	for _, s := range current.Servers {
+		present[s.ID] = s
+	}
+
+	seen := make(map[string]bool, len(existing.Servers))
+	for i, s := range existing.Servers {
+		seen[s.ID] = true
+		if fresh, ok := present[s.ID]; ok {
+			if fresh.AgentKey == "" {
+				fresh.AgentKey = s.AgentKey
+			}
+			existing.Servers[i] = fresh
+			updated++
+			continue
+		}
+		if s.RemovedAt == nil {
+			removedAt := current.ExportedAt
+			existing.Servers[i].RemovedAt = &removedAt
+			removed++
+		}
+	}
+
+	for _, s := range current.Servers {
+		if !seen[s.ID] {
+			existing.Servers = append(existing.Servers, s)
+			added++
+		}
+	}
+
+	if stripKeys {
+		for i := range existing.Servers {
+			existing.Servers[i].AgentKey = ""
+		}
+	}
+
+	existing.Version = exportFormatVersion
+	existing.ExportedAt = current.ExportedAt
+	existing.CloudURL = current.CloudURL
+	return added, updated, removed
+}
+
+// isYAMLFile reports whether a path should be read and written as YAML
+func isYAMLFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// readServerExport reads an export file, returning nil if it doesn't exist
+func readServerExport(path string) (*ServerExport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var export ServerExport
+	if isYAMLFile(path) {
+		err = yaml.Unmarshal(data, &export)
+	} else {
+		err = json.Unmarshal(data, &export)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid server export: %w", path, err)
+	}
+
+	switch {
+	case export.Version == 0:
+		return nil, fmt.Errorf("%s is not a server export (missing version)", path)
+	case export.Version > exportFormatVersion:
+		return nil, fmt.Errorf("%s was written by a newer vstats (format v%d, this version supports v%d)",
+			path, export.Version, exportFormatVersion)
+	}
+	return &export, nil
+}
+
+// writeServerExport writes an export file as JSON or YAML based on its
+// extension. A file holding agent keys is made owner-only, even when it
+// already existed with wider permissions.
+func writeServerExport(path string, export *ServerExport) error {
+	var data []byte
+	var err error
+	if isYAMLFile(path) {
+		data, err = yaml.Marshal(export)
+	} else {
+		data, err = json.MarshalIndent(export, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+	// Restrict an existing file before the keys are written into it
+	for _, s := range export.Servers {
+		if s.AgentKey == "" {
+			continue
+		}
+		if err := os.Chmod(path, 0600); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		break
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func init() {
+	serverCmd.AddCommand(serverExportCmd)
+
+	serverExportCmd.Flags().StringP("file", "f", "", "write the export to this file instead of stdout")
+	serverExportCmd.Flags().Bool("append", false, "merge into an existing export file, marking missing servers as removed")
+	serverExportCmd.Flags().Bool("include-keys", false, "include agent keys (treat the export as a secret)")
+	serverExportCmd.Flags().Bool("strip-keys", false, "with --append, remove agent keys already in the file")
+	serverExportCmd.Flags().Bool("include-metrics", false, "include each server's current metrics")
+}
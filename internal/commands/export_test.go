@@ -0,0 +1,36 @@
+package commands
+
+import "testing"
+
+func TestMergeServerExportKeepsKeys(t *testing.T) {
+	existing := func() *ServerExport {
+		return &ServerExport{Servers: []ExportedServer{
+			{ID: "a", Name: "web-01", AgentKey: "key-a"},
+			{ID: "b", Name: "gone", AgentKey: "key-b"},
+		}}
+	}
+	current := &ServerExport{Servers: []ExportedServer{
+		{ID: "a", Name: "web-01-renamed"},
+		{ID: "c", Name: "new"},
+	}}
+
+	merged := existing()
+	added, updated, removed := mergeServerExport(merged, current, false)
+	if added != 1 || updated != 1 || removed != 1 {
+		t.Errorf("merge = %d added, %d updated, %d removed, want 1 each", added, updated, removed)
+	}
+	if got := merged.Servers[0]; got.Name != "web-01-renamed" || got.AgentKey != "key-a" {
+		t.Errorf("updated server = %+v, want the new name and the old key", got)
+	}
+	if got := merged.Servers[1].AgentKey; got != "key-b" {
+		t.Errorf("removed server key = %q, want key-b", got)
+	}
+
+	stripped := existing()
+	mergeServerExport(stripped, current, true)
+	for _, s := range stripped.Servers {
+		if s.AgentKey != "" {
+			t.Errorf("%s kept key %q with stripKeys", s.ID, s.AgentKey)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// metricFilter selects servers whose metrics are above the given percentages
+type metricFilter struct {
+	CPUAbove  *float64
+	MemAbove  *float64
+	DiskAbove *float64
+}
+
+// metricFilterFromFlags reads the --cpu-above/--mem-above/--disk-above flags
+func metricFilterFromFlags(cmd *cobra.Command) metricFilter {
+	var f metricFilter
+	read := func(name string) *float64 {
+		if !cmd.Flags().Changed(name) {
+			return nil
+		}
+		v, _ := cmd.Flags().GetFloat64(name)
+		return &v
+	}
+	f.CPUAbove = read("cpu-above")
+	f.MemAbove = read("mem-above")
+	f.DiskAbove = read("disk-above")
+	return f
+}
+
+// active reports whether any metric filter was given
+func (f metricFilter) active() bool {
+	return f.CPUAbove != nil || f.MemAbove != nil || f.DiskAbove != nil
+}
+
+// apply returns the servers matching every given threshold, along with the
+// number of servers excluded because they have no metrics
+func (f metricFilter) apply(servers []Server) ([]Server, int) {
+	if !f.active() {
+		return servers, 0
+	}
+
+	matched := make([]Server, 0, len(servers))
+	skipped := 0
+	for _, s := range servers {
+		if s.Metrics == nil {
+			skipped++
+			continue
+		}
+		if f.matches(s.Metrics) {
+			matched = append(matched, s)
+		}
+	}
+	return matched, skipped
+}
+
+// matches reports whether metrics exceed every given threshold
+func (f metricFilter) matches(m *ServerMetrics) bool {
+	if f.CPUAbove != nil {
+		if m.CPUUsage == nil || *m.CPUUsage <= *f.CPUAbove {
+			return false
+		}
+	}
+	if f.MemAbove != nil {
+		pct, ok := usagePercent(m.MemoryUsed, m.MemoryTotal)
+		if !ok || pct <= *f.MemAbove {
+			return false
+		}
+	}
+	if f.DiskAbove != nil {
+		pct, ok := usagePercent(m.DiskUsed, m.DiskTotal)
+		if !ok || pct <= *f.DiskAbove {
+			return false
+		}
+	}
+	return true
+}
+
+// filterStatuses lists the values accepted by --status
+var filterStatuses = []string{"online", "offline", "pending", "paused"}
+
+// serverFilter selects servers by status, a name/host/IP substring, and tags
+type serverFilter struct {
+	Status string
+	Text   string
+	Tags   map[string]string
+}
+
+// serverFilterFromFlags reads and validates the --status/--filter/--tag flags
+func serverFilterFromFlags(cmd *cobra.Command) (serverFilter, error) {
+	status, _ := cmd.Flags().GetString("status")
+	text, _ := cmd.Flags().GetString("filter")
+	f := serverFilter{Status: strings.ToLower(status), Text: strings.ToLower(text)}
+
+	if pairs, _ := cmd.Flags().GetStringArray("tag"); len(pairs) > 0 {
+		tags, err := parseTagFlags(pairs)
+		if err != nil {
+			return f, err
+		}
+		f.Tags = tags
+	}
+
+	if f.Status != "" {
+		valid := false
+		for _, s := range filterStatuses {
+			if s == f.Status {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return f, fmt.Errorf("invalid --status %q (valid: %s)", status, strings.Join(filterStatuses, ", "))
+		}
+	}
+	return f, nil
+}
+
+// active reports whether a status, text, or tag filter was given
+func (f serverFilter) active() bool {
+	return f.Status != "" || f.Text != "" || len(f.Tags) > 0
+}
+
+// apply returns the servers matching the status, text, and tag filters
+func (f serverFilter) apply(servers []Server) []Server {
+	if !f.active() {
+		return servers
+	}
+
+	matched := make([]Server, 0, len(servers))
+	for _, s := range servers {
+		if f.matches(s) {
+			matched = append(matched, s)
+		}
+	}
+	return matched
+}
+
+// matches reports whether a server has the status and every tag, and
+// contains the text in its name, hostname, or IP address (case-insensitively)
+func (f serverFilter) matches(s Server) bool {
+	if f.Status != "" && strings.ToLower(s.Status) != f.Status {
+		return false
+	}
+	for key, value := range f.Tags {
+		if v, ok := s.Tags[key]; !ok || v != value {
+			return false
+		}
+	}
+	if f.Text == "" {
+		return true
+	}
+	for _, field := range []*string{&s.Name, s.Hostname, s.IPAddress} {
+		if field != nil && strings.Contains(strings.ToLower(*field), f.Text) {
+			return true
+		}
+	}
+	return false
+}
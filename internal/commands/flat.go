@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+)
+
+// flattenMetrics turns metrics into a single-level object for TSDB
+// ingestion: plain numbers keyed with their unit, nil metrics left out, and
+// the server and scrape time included so each object stands alone. GPU
+// metrics are keyed by index, e.g. gpu_0_utilization.
+func flattenMetrics(server *Server, m *ServerMetrics, collectedAt time.Time) map[string]interface{} {
+	flat := map[string]interface{}{
+		"server_id":    server.ID,
+		"server_name":  server.Name,
+		"collected_at": collectedAt.UTC().Format(time.RFC3339),
+	}
+	addFloat := func(key string, v *float64) {
+		if v != nil {
+			flat[key] = *v
+		}
+	}
+	addInt64 := func(key string, v *int64) {
+		if v != nil {
+			flat[key] = *v
+		}
+	}
+	addInt := func(key string, v *int) {
+		if v != nil {
+			flat[key] = *v
+		}
+	}
+
+	addFloat("cpu_usage", m.CPUUsage)
+	addInt("cpu_cores", m.CPUCores)
+	addFloat("cpu_temp_celsius", m.CPUTempC)
+	addFloat("load_avg_1", m.LoadAvg1)
+	addFloat("load_avg_5", m.LoadAvg5)
+	addFloat("load_avg_15", m.LoadAvg15)
+	addInt64("memory_total_bytes", m.MemoryTotal)
+	addInt64("memory_used_bytes", m.MemoryUsed)
+	addInt64("memory_free_bytes", m.MemoryFree)
+	if pct, ok := usagePercent(m.MemoryUsed, m.MemoryTotal); ok {
+		flat["memory_usage"] = pct
+	}
+	addInt64("disk_total_bytes", m.DiskTotal)
+	addInt64("disk_used_bytes", m.DiskUsed)
+	addInt64("disk_free_bytes", m.DiskFree)
+	if pct, ok := usagePercent(m.DiskUsed, m.DiskTotal); ok {
+		flat["disk_usage"] = pct
+	}
+	addInt("process_count", m.ProcessCount)
+	addInt64("net_rx_bytes_per_second", m.NetRxBytes)
+	addInt64("net_tx_bytes_per_second", m.NetTxBytes)
+
+	for _, gpu := range m.GPUs {
+		prefix := fmt.Sprintf("gpu_%d_", gpu.Index)
+		addFloat(prefix+"utilization", gpu.Utilization)
+		addInt64(prefix+"memory_used_bytes", gpu.MemoryUsed)
+		addInt64(prefix+"memory_total_bytes", gpu.MemoryTotal)
+		addFloat(prefix+"temperature_celsius", gpu.Temperature)
+	}
+	return flat
+}
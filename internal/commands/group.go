@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// serverGroupKeys lists the attributes servers can be grouped by
+var serverGroupKeys = []string{"status", "os", "tag"}
+
+// noGroupValue labels servers that don't have the grouped attribute
+const noGroupValue = "(none)"
+
+// serverGroup is a named section of servers
+type serverGroup struct {
+	Name    string
+	Servers []Server
+}
+
+// validateGroupKey checks that key is a supported group-by key
+func validateGroupKey(key string) error {
+	for _, k := range serverGroupKeys {
+		if k == key {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid group-by key: %s (available: %s)", key, strings.Join(serverGroupKeys, ", "))
+}
+
+// serverGroupValues returns the groups a server belongs to. A server is in
+// one group per tag, as key=value, when grouping by tag.
+func serverGroupValues(s Server, key string) []string {
+	var values []string
+	switch key {
+	case "status":
+		values = append(values, strings.ToLower(s.Status))
+	case "os":
+		if osType := ptrString(s.OSType); osType != "-" {
+			values = append(values, osType)
+		}
+	case "tag":
+		for k, v := range s.Tags {
+			values = append(values, k+"="+v)
+		}
+		sort.Strings(values)
+	}
+	if len(values) == 0 || values[0] == "" {
+		return []string{noGroupValue}
+	}
+	return values
+}
+
+// groupServers splits servers into groups by key, keeping the existing
+// order within each group. Status groups are ordered healthiest first,
+// other groups alphabetically, with servers lacking the attribute (or
+// without tags) last.
+func groupServers(servers []Server, key string) []serverGroup {
+	index := make(map[string]int)
+	var groups []serverGroup
+	for _, s := range servers {
+		for _, name := range serverGroupValues(s, key) {
+			i, ok := index[name]
+			if !ok {
+				i = len(groups)
+				index[name] = i
+				groups = append(groups, serverGroup{Name: name})
+			}
+			groups[i].Servers = append(groups[i].Servers, s)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i].Name, groups[j].Name
+		if (a == noGroupValue) != (b == noGroupValue) {
+			return b == noGroupValue
+		}
+		if key == "status" {
+			ra, okA := statusRank[a]
+			rb, okB := statusRank[b]
+			if !okA {
+				ra = len(statusRank)
+			}
+			if !okB {
+				rb = len(statusRank)
+			}
+			if ra != rb {
+				return ra < rb
+			}
+		}
+		return a < b
+	})
+	return groups
+}
+
+// groupedServerMap converts groups to a group name → servers map for
+// structured output
+func groupedServerMap(groups []serverGroup) map[string][]Server {
+	out := make(map[string][]Server, len(groups))
+	for _, g := range groups {
+		out[g.Name] = g.Servers
+	}
+	return out
+}
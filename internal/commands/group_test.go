@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupServersByTag(t *testing.T) {
+	servers := []Server{
+		{Name: "web-01", Tags: map[string]string{"env": "prod", "role": "web"}},
+		{Name: "scratch"},
+		{Name: "db-01", Tags: map[string]string{"env": "prod"}},
+		{Name: "web-02", Tags: map[string]string{"env": "staging", "role": "web"}},
+	}
+
+	got := make(map[string][]string)
+	var order []string
+	for _, g := range groupServers(servers, "tag") {
+		order = append(order, g.Name)
+		for _, s := range g.Servers {
+			got[g.Name] = append(got[g.Name], s.Name)
+		}
+	}
+
+	wantOrder := []string{"env=prod", "env=staging", "role=web", noGroupValue}
+	if !reflect.DeepEqual(order, wantOrder) {
+		t.Errorf("groups = %v, want %v", order, wantOrder)
+	}
+	want := map[string][]string{
+		"env=prod":    {"web-01", "db-01"},
+		"env=staging": {"web-02"},
+		"role=web":    {"web-01", "web-02"},
+		noGroupValue:  {"scratch"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("members = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,425 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SeriesStats summarizes a series of metric samples
+type SeriesStats struct {
+	Min   float64 `json:"min" yaml:"min"`
+	Avg   float64 `json:"avg" yaml:"avg"`
+	Max   float64 `json:"max" yaml:"max"`
+	Count int     `json:"count" yaml:"count"`
+}
+
+// HistoryGap represents a stretch of history with no data points
+type HistoryGap struct {
+	Start    time.Time     `json:"start" yaml:"start"`
+	End      time.Time     `json:"end" yaml:"end"`
+	Duration time.Duration `json:"duration" yaml:"duration"`
+}
+
+// HistoryStats summarizes a metrics history window
+type HistoryStats struct {
+	Range       string       `json:"range" yaml:"range"`
+	Samples     int          `json:"samples" yaml:"samples"`
+	CPU         *SeriesStats `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+	MemoryUsed  *SeriesStats `json:"memory_used,omitempty" yaml:"memory_used,omitempty"`
+	OfflineGaps []HistoryGap `json:"offline_gaps" yaml:"offline_gaps"`
+}
+
+// historyColumn is one metric column of the server history table
+type historyColumn struct {
+	Metric string
+	Header string
+	Value  func(d MetricsData) *float64
+	Format func(v float64) string
+}
+
+// historyColumns lists the metric columns in table order
+var historyColumns = []historyColumn{
+	{"cpu", "CPU", func(d MetricsData) *float64 { return d.CPUUsage }, formatPercent},
+	{"memory", "MEM USED", func(d MetricsData) *float64 { return int64PtrToFloat(d.MemoryUsed) },
+		func(v float64) string { return formatBytes(int64(v)) }},
+	{"disk", "DISK USED", func(d MetricsData) *float64 { return int64PtrToFloat(d.DiskUsed) },
+		func(v float64) string { return formatBytes(int64(v)) }},
+}
+
+// selectHistoryColumns returns the columns shown for a --metric value:
+// one metric, or all of them
+func selectHistoryColumns(metric string) ([]historyColumn, error) {
+	if metric == "all" {
+		return historyColumns, nil
+	}
+	keys := []string{"all"}
+	for _, c := range historyColumns {
+		if c.Metric == metric {
+			return []historyColumn{c}, nil
+		}
+		keys = append(keys, c.Metric)
+	}
+	return nil, fmt.Errorf("invalid --metric %q (valid: %s)", metric, strings.Join(keys, ", "))
+}
+
+// cell formats the column's value for a data point, "-" when missing
+func (c historyColumn) cell(d MetricsData) string {
+	if v := c.Value(d); v != nil {
+		return c.Format(*v)
+	}
+	return "-"
+}
+
+// stats summarizes the column across data, skipping missing values
+func (c historyColumn) stats(data []MetricsData) *SeriesStats {
+	var values []float64
+	for _, d := range data {
+		if v := c.Value(d); v != nil {
+			values = append(values, *v)
+		}
+	}
+	return computeSeriesStats(values)
+}
+
+// gapIntervalFactor is how many typical sample intervals must pass
+// without data before a stretch is considered an offline gap
+const gapIntervalFactor = 3
+
+// summarizeHistory computes aggregate statistics for a metrics history
+func summarizeHistory(history *MetricsHistory) *HistoryStats {
+	var cpu, mem []float64
+	for _, d := range history.Data {
+		if d.CPUUsage != nil {
+			cpu = append(cpu, *d.CPUUsage)
+		}
+		if d.MemoryUsed != nil {
+			mem = append(mem, float64(*d.MemoryUsed))
+		}
+	}
+
+	return &HistoryStats{
+		Range:       history.Range,
+		Samples:     len(history.Data),
+		CPU:         computeSeriesStats(cpu),
+		MemoryUsed:  computeSeriesStats(mem),
+		OfflineGaps: detectOfflineGaps(history.Data),
+	}
+}
+
+// computeSeriesStats returns min/avg/max for a series, or nil if it is empty
+func computeSeriesStats(values []float64) *SeriesStats {
+	if len(values) == 0 {
+		return nil
+	}
+
+	stats := &SeriesStats{Min: values[0], Max: values[0], Count: len(values)}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+		if v < stats.Min {
+			stats.Min = v
+		}
+		if v > stats.Max {
+			stats.Max = v
+		}
+	}
+	stats.Avg = sum / float64(len(values))
+	return stats
+}
+
+// detectOfflineGaps finds stretches between samples that are much longer
+// than the typical sample interval, which indicate the agent was offline
+func detectOfflineGaps(data []MetricsData) []HistoryGap {
+	gaps := []HistoryGap{}
+	if len(data) < 3 {
+		return gaps
+	}
+
+	points := make([]time.Time, len(data))
+	for i, d := range data {
+		points[i] = d.CollectedAt
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Before(points[j]) })
+
+	intervals := make([]time.Duration, 0, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		intervals = append(intervals, points[i].Sub(points[i-1]))
+	}
+
+	// Use the median interval as the expected sampling rate
+	sorted := append([]time.Duration(nil), intervals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	expected := sorted[len(sorted)/2]
+	if expected <= 0 {
+		return gaps
+	}
+
+	for i, interval := range intervals {
+		if interval > expected*gapIntervalFactor {
+			gaps = append(gaps, HistoryGap{
+				Start:    points[i],
+				End:      points[i+1],
+				Duration: interval,
+			})
+		}
+	}
+	return gaps
+}
+
+// findAnomalies flags the data points that deviate from the mean of their
+// neighbors (up to window points on each side) by more than delta. CPU
+// deviation is measured in percentage points; memory and disk deviation is
+// measured as a percentage of the neighbors' mean.
+func findAnomalies(data []MetricsData, window int, delta float64) []bool {
+	cpu := make([]*float64, len(data))
+	mem := make([]*float64, len(data))
+	disk := make([]*float64, len(data))
+	for i, d := range data {
+		cpu[i] = d.CPUUsage
+		mem[i] = int64PtrToFloat(d.MemoryUsed)
+		disk[i] = int64PtrToFloat(d.DiskUsed)
+	}
+
+	anomalies := make([]bool, len(data))
+	for i := range data {
+		anomalies[i] = deviates(cpu, i, window, delta, false) ||
+			deviates(mem, i, window, delta, true) ||
+			deviates(disk, i, window, delta, true)
+	}
+	return anomalies
+}
+
+// deviates reports whether series[i] differs from its rolling neighborhood
+// mean by more than delta, either absolutely or relative to the mean
+func deviates(series []*float64, i, window int, delta float64, relative bool) bool {
+	if series[i] == nil {
+		return false
+	}
+
+	sum, count := 0.0, 0
+	for j := i - window; j <= i+window; j++ {
+		if j < 0 || j >= len(series) || j == i || series[j] == nil {
+			continue
+		}
+		sum += *series[j]
+		count++
+	}
+	if count == 0 {
+		return false
+	}
+
+	mean := sum / float64(count)
+	diff := *series[i] - mean
+	if diff < 0 {
+		diff = -diff
+	}
+	if relative {
+		if mean == 0 {
+			return diff > 0
+		}
+		diff = diff / mean * 100
+	}
+	return diff > delta
+}
+
+// int64PtrToFloat converts an optional integer to an optional float
+func int64PtrToFloat(v *int64) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+// rangePattern matches literal history ranges such as 30m, 6h, 7d, or 2w
+var rangePattern = regexp.MustCompile(`^[1-9][0-9]*[mhdw]$`)
+
+// History resolution limits. Raw agent samples are only offered for short
+// ranges, and bucketed resolutions are capped by the number of points
+// they would produce.
+const (
+	rawResolution      = "raw"
+	rawHistoryMaxRange = 24 * time.Hour
+	maxHistoryPoints   = 20000
+)
+
+// parseRangeDuration converts a literal range such as 30m, 6h, 7d, or 2w
+// into a duration
+func parseRangeDuration(s string) (time.Duration, bool) {
+	if !rangePattern.MatchString(s) {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil {
+		return 0, false
+	}
+	unit := map[byte]time.Duration{
+		'm': time.Minute,
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+	}[s[len(s)-1]]
+	return time.Duration(n) * unit, true
+}
+
+// validateResolution checks a --resolution value and that it makes sense
+// for the requested range
+func validateResolution(resolution, rangeStr string) error {
+	if resolution == "" {
+		return nil
+	}
+
+	span, spanOK := parseRangeDuration(rangeStr)
+	if resolution == rawResolution {
+		if spanOK && span > rawHistoryMaxRange {
+			return fmt.Errorf("raw resolution is limited to ranges of %s or less (%s of raw samples is too much data); use e.g. --resolution 5m or 1h",
+				formatDuration(rawHistoryMaxRange), rangeStr)
+		}
+		return nil
+	}
+
+	step, ok := parseRangeDuration(resolution)
+	if !ok {
+		return fmt.Errorf("invalid resolution: %s (use raw or a duration such as 1m, 5m, or 1h)", resolution)
+	}
+	if !spanOK {
+		return nil
+	}
+	if step >= span {
+		return fmt.Errorf("resolution %s must be finer than the range %s", resolution, rangeStr)
+	}
+	if points := int(span / step); points > maxHistoryPoints {
+		return fmt.Errorf("resolution %s over %s would return %d points (max %d); use a coarser resolution",
+			resolution, rangeStr, points, maxHistoryPoints)
+	}
+	return nil
+}
+
+// resolveRange expands a named range preset from config, falling back to
+// the literal range when the name isn't a preset
+func resolveRange(name string) string {
+	if preset, ok := cfg.Ranges[name]; ok {
+		return preset
+	}
+	return name
+}
+
+// setRangePreset applies a ranges.<name> config key
+func setRangePreset(key, value string) error {
+	name := strings.TrimPrefix(key, "ranges.")
+	if name == "" || strings.Contains(name, ".") {
+		return fmt.Errorf("invalid range preset name: %q", name)
+	}
+	if rangePattern.MatchString(name) {
+		return fmt.Errorf("range preset name %q looks like a literal range; pick a descriptive name", name)
+	}
+	if !rangePattern.MatchString(value) {
+		return fmt.Errorf("invalid range: %s (use a number followed by m, h, d, or w, e.g. 6h)", value)
+	}
+
+	if cfg.Ranges == nil {
+		cfg.Ranges = make(map[string]string)
+	}
+	cfg.Ranges[name] = value
+	return nil
+}
+
+// historyCSVHeader is the column header of history CSV exports
+var historyCSVHeader = []string{"collected_at", "cpu_usage", "memory_used", "disk_used"}
+
+// streamHistoryCSV writes a server's history as CSV row by row while it is
+// being downloaded, so long ranges don't have to fit in memory
+func streamHistoryCSV(client *Client, server *Server, rangeStr, resolution string) error {
+	w := csv.NewWriter(dataOut)
+	if err := w.Write(historyCSVHeader); err != nil {
+		return err
+	}
+
+	err := client.StreamServerHistory(server.ID, rangeStr, resolution, func(d MetricsData) error {
+		return w.Write([]string{
+			d.CollectedAt.UTC().Format(time.RFC3339),
+			optionalFloat(d.CPUUsage),
+			optionalInt64(d.MemoryUsed),
+			optionalInt64(d.DiskUsed),
+		})
+	})
+	w.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to get history: %w", err)
+	}
+	return w.Error()
+}
+
+// prometheusSeries describes one history metric in the Prometheus text format
+type prometheusSeries struct {
+	Name  string
+	Help  string
+	Value func(MetricsData) (float64, bool)
+}
+
+// historyPrometheusSeries are the metric families written by -o prometheus
+var historyPrometheusSeries = []prometheusSeries{
+	{"vstats_cpu_usage_percent", "CPU usage in percent.", func(d MetricsData) (float64, bool) {
+		if d.CPUUsage == nil {
+			return 0, false
+		}
+		return *d.CPUUsage, true
+	}},
+	{"vstats_memory_used_bytes", "Memory in use in bytes.", func(d MetricsData) (float64, bool) {
+		if d.MemoryUsed == nil {
+			return 0, false
+		}
+		return float64(*d.MemoryUsed), true
+	}},
+	{"vstats_disk_used_bytes", "Disk space in use in bytes.", func(d MetricsData) (float64, bool) {
+		if d.DiskUsed == nil {
+			return 0, false
+		}
+		return float64(*d.DiskUsed), true
+	}},
+}
+
+// prometheusLabelEscaper escapes a label value for the exposition format,
+// which only escapes backslash, double quote, and newline
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// writeHistoryPrometheus writes history in the Prometheus text exposition
+// format, with millisecond timestamps on every sample for backfilling
+func writeHistoryPrometheus(server *Server, history *MetricsHistory) error {
+	labels := fmt.Sprintf(`{server="%s",server_id="%s"}`,
+		prometheusLabelEscaper.Replace(server.Name), prometheusLabelEscaper.Replace(server.ID))
+	w := bufio.NewWriter(dataOut)
+	for _, series := range historyPrometheusSeries {
+		fmt.Fprintf(w, "# HELP %s %s\n", series.Name, series.Help)
+		fmt.Fprintf(w, "# TYPE %s gauge\n", series.Name)
+		for _, d := range history.Data {
+			if value, ok := series.Value(d); ok {
+				fmt.Fprintf(w, "%s%s %s %d\n", series.Name, labels,
+					strconv.FormatFloat(value, 'f', -1, 64), d.CollectedAt.UnixMilli())
+			}
+		}
+	}
+	return w.Flush()
+}
+
+// optionalFloat formats an optional float, empty when missing
+func optionalFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+// optionalInt64 formats an optional integer, empty when missing
+func optionalInt64(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(*v, 10)
+}
@@ -0,0 +1,18 @@
+package commands
+
+import "testing"
+
+func TestPrometheusLabelEscaper(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"web-01", "web-01"},
+		{`say "hi"`, `say \"hi\"`},
+		{`C:\srv`, `C:\\srv`},
+		{"two\nlines", `two\nlines`},
+		{"tab\tand ünïcode", "tab\tand ünïcode"},
+	}
+	for _, tt := range tests {
+		if got := prometheusLabelEscaper.Replace(tt.in); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// ImportResult is the outcome of importing one server
+type ImportResult struct {
+	Name     string `json:"name" yaml:"name"`
+	ID       string `json:"id,omitempty" yaml:"id,omitempty"`
+	AgentKey string `json:"agent_key,omitempty" yaml:"agent_key,omitempty"`
+	Result   string `json:"result" yaml:"result"`
+	Error    string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Import results
+const (
+	importCreated = "created"
+	importSkipped = "skipped"
+	importFailed  = "failed"
+)
+
+// serverImportCmd recreates servers from an export file
+var serverImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Create servers from an export file",
+	Long: `Create the servers listed in a file written by 'vstats server export',
+for example to bootstrap a new account from a backup.
+
+Servers are matched by name. Those that already exist are skipped, so
+re-running an import is safe; pass --skip-existing=false to create them
+again anyway. Entries marked removed in an --append export are ignored.
+Tags in the export are applied to the new servers. The new agent keys are
+printed, since each created server gets a fresh key.
+
+Use --dry-run to preview which servers would be created.
+
+Examples:
+  vstats server import servers.json --dry-run
+  vstats server import servers.json
+  vstats server import inventory.yaml -o json > new-keys.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireLogin(); err != nil {
+			return err
+		}
+
+		export, err := readServerExport(args[0])
+		if err != nil {
+			return err
+		}
+		if export == nil {
+			return fmt.Errorf("export file not found: %s", args[0])
+		}
+		skipExisting, _ := cmd.Flags().GetBool("skip-existing")
+
+		client := NewClient()
+		servers, err := client.ListServers()
+		if err != nil {
+			return fmt.Errorf("failed to list servers: %w", err)
+		}
+		existing := make(map[string]bool, len(servers))
+		for _, s := range servers {
+			existing[s.Name] = true
+		}
+
+		var results []ImportResult
+		failed := 0
+		for _, entry := range export.Servers {
+			if entry.RemovedAt != nil {
+				continue
+			}
+			if skipExisting && existing[entry.Name] {
+				if dryRun {
+					fmt.Printf("(dry-run) would skip server '%s': already exists\n", entry.Name)
+				}
+				results = append(results, ImportResult{Name: entry.Name, Result: importSkipped})
+				continue
+			}
+			if err := validateServerName(entry.Name); err != nil {
+				if dryRun {
+					fmt.Printf("(dry-run) can't create server: %v\n", err)
+				}
+				failed++
+				results = append(results, ImportResult{Name: entry.Name, Result: importFailed, Error: err.Error()})
+				continue
+			}
+			if dryRun {
+				printDryRun("POST", "/api/servers", map[string]string{"name": entry.Name},
+					"would create server '%s'", entry.Name)
+				continue
+			}
+
+			server, err := client.CreateServer(entry.Name)
+			if err != nil {
+				failed++
+				results = append(results, ImportResult{Name: entry.Name, Result: importFailed, Error: err.Error()})
+				continue
+			}
+			existing[entry.Name] = true
+			if len(entry.Tags) > 0 {
+				if _, err := client.AddServerTags(server.ID, entry.Tags); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: created '%s' but failed to apply its tags: %v\n", entry.Name, err)
+				}
+			}
+			results = append(results, ImportResult{Name: entry.Name, ID: server.ID, AgentKey: server.AgentKey, Result: importCreated})
+		}
+		if dryRun {
+			return nil
+		}
+		invalidateServerCache()
+
+		switch outputFmt {
+		case "json":
+			err = OutputJSON(results)
+		case "jsonl":
+			err = OutputJSONL(results)
+		case "yaml":
+			err = OutputYAML(results)
+		case "csv":
+			err = OutputCSV(results)
+		case "template":
+			err = OutputTemplate(results)
+		default:
+			printImportResults(results)
+		}
+		if err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d imports failed", failed, len(results))
+		}
+		return nil
+	},
+}
+
+// printImportResults renders a table of import results and a summary
+func printImportResults(results []ImportResult) {
+	if len(results) == 0 {
+		fmt.Println("No servers to import.")
+		return
+	}
+
+	created, skipped := 0, 0
+	table := NewTable("NAME", "ID", "AGENT KEY", "RESULT")
+	for _, r := range results {
+		result := r.Result
+		switch r.Result {
+		case importCreated:
+			created++
+			result = color(themeColor(RoleOnline), "✓ created")
+		case importSkipped:
+			skipped++
+			result = color(ColorGray, "- exists")
+		case importFailed:
+			result = color(themeColor(RoleCritical), "✗ "+r.Error)
+		}
+		table.AddRow(r.Name, orDash(r.ID), orDash(r.AgentKey), result)
+	}
+	table.Render()
+	fmt.Println()
+	fmt.Printf("%d created, %d skipped, %d failed\n", created, skipped, len(results)-created-skipped)
+}
+
+func init() {
+	serverCmd.AddCommand(serverImportCmd)
+
+	serverImportCmd.Flags().Bool("skip-existing", true, "skip servers whose name already exists")
+}
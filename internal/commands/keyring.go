@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// credentialStoreKeyring is the credential_store value that keeps tokens
+// in the OS secret store instead of the config file
+const credentialStoreKeyring = "keyring"
+
+// keyringService is the service name tokens are stored under
+const keyringService = "vstats-cli"
+
+// Keyring accounts for the stored secrets
+const (
+	keyringTokenAccount   = "token"
+	keyringRefreshAccount = "refresh_token"
+)
+
+var (
+	// errKeyringUnavailable means no usable secret store was found
+	errKeyringUnavailable = errors.New("system keyring is not available")
+	// errKeyringNotFound means the secret isn't stored
+	errKeyringNotFound = errors.New("secret not found in keyring")
+)
+
+// keyringLoaded records that the keyring was read, or found empty, so that
+// a save doesn't mistake a token that was never loaded for a logout. It
+// stays false after a failed read, and the next use tries again.
+var keyringLoaded bool
+
+// useKeyring reports whether credentials should live in the keyring
+func useKeyring() bool {
+	return cfg.CredentialStore == credentialStoreKeyring
+}
+
+// loadKeyringTokens fills in the tokens from the keyring once. A token
+// already in the config file (e.g. before migrating) takes precedence.
+func loadKeyringTokens() {
+	if !useKeyring() || keyringLoaded {
+		return
+	}
+	if cfg.Token != "" {
+		keyringLoaded = true
+		return
+	}
+
+	token, err := keyringGet(keyringTokenAccount)
+	if err != nil {
+		// Without a keyring there is simply no stored token; saving one warns
+		if errors.Is(err, errKeyringNotFound) || errors.Is(err, errKeyringUnavailable) {
+			keyringLoaded = true
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read token from keyring: %v\n", err)
+		}
+		return
+	}
+	keyringLoaded = true
+	cfg.Token = token
+	if refresh, err := keyringGet(keyringRefreshAccount); err == nil {
+		cfg.RefreshToken = refresh
+	}
+}
+
+// storeKeyringTokens moves the tokens into the keyring and returns the
+// config to write to disk, with the tokens removed. If the keyring can't
+// be used the tokens stay in the file and a warning is printed.
+func storeKeyringTokens() Config {
+	onDisk := *cfg
+	if !useKeyring() {
+		return onDisk
+	}
+	loadKeyringTokens()
+
+	secrets := []struct{ account, value string }{
+		{keyringTokenAccount, cfg.Token},
+		{keyringRefreshAccount, cfg.RefreshToken},
+	}
+	for _, s := range secrets {
+		var err error
+		if s.value == "" {
+			// An empty token is only a logout if the stored one was read;
+			// after a failed read it would wipe a login we never saw
+			if !keyringLoaded {
+				continue
+			}
+			err = keyringDelete(s.account)
+			if errors.Is(err, errKeyringNotFound) || errors.Is(err, errKeyringUnavailable) {
+				err = nil
+			}
+		} else {
+			err = keyringSet(s.account, s.value)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: keyring unavailable (%v); storing credentials in the config file\n", err)
+			return onDisk
+		}
+	}
+
+	onDisk.Token = ""
+	onDisk.RefreshToken = ""
+	return onDisk
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// macOS stores secrets in the login Keychain through the security tool
+
+func keyringGet(account string) (string, error) {
+	out, err := securityCommand("find-generic-password", "-s", keyringService, "-a", account, "-w")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(out, "\n"), nil
+}
+
+// keyringSet runs security in interactive mode and sends the command on
+// stdin, so the secret never appears in the process list
+func keyringSet(account, secret string) error {
+	path, err := exec.LookPath("security")
+	if err != nil {
+		return errKeyringUnavailable
+	}
+	line := strings.Join([]string{
+		"add-generic-password", "-U",
+		"-s", securityQuote(keyringService),
+		"-a", securityQuote(account),
+		"-w", securityQuote(secret),
+	}, " ")
+	cmd := exec.Command(path, "-i")
+	cmd.Stdin = strings.NewReader(line + "\n")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+	// security -i reports a failed command on stderr but still exits 0
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return fmt.Errorf("security: %s", msg)
+	}
+	return nil
+}
+
+// securityQuote quotes an argument for security's interactive mode
+func securityQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+func keyringDelete(account string) error {
+	_, err := securityCommand("delete-generic-password", "-s", keyringService, "-a", account)
+	return err
+}
+
+// securityCommand runs /usr/bin/security, mapping "item not found" (exit
+// status 44) to errKeyringNotFound
+func securityCommand(args ...string) (string, error) {
+	path, err := exec.LookPath("security")
+	if err != nil {
+		return "", errKeyringUnavailable
+	}
+	out, err := exec.Command(path, args...).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 44 {
+		return "", errKeyringNotFound
+	}
+	return string(out), err
+}
@@ -0,0 +1,52 @@
+//go:build !darwin && !windows
+
+package commands
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// Linux and other Unix systems use the Secret Service (libsecret) through
+// secret-tool
+
+func keyringGet(account string) (string, error) {
+	out, err := secretTool("", "lookup", "service", keyringService, "account", account)
+	if err != nil {
+		return "", err
+	}
+	if out == "" {
+		return "", errKeyringNotFound
+	}
+	return strings.TrimRight(out, "\n"), nil
+}
+
+func keyringSet(account, secret string) error {
+	_, err := secretTool(secret, "store", "--label=vStats CLI "+account, "service", keyringService, "account", account)
+	return err
+}
+
+func keyringDelete(account string) error {
+	_, err := secretTool("", "clear", "service", keyringService, "account", account)
+	return err
+}
+
+// secretTool runs secret-tool with the secret (if any) on stdin. lookup
+// exits 1 with no output when nothing is stored.
+func secretTool(stdin string, args ...string) (string, error) {
+	path, err := exec.LookPath("secret-tool")
+	if err != nil {
+		return "", errKeyringUnavailable
+	}
+	cmd := exec.Command(path, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(out) == 0 && len(exitErr.Stderr) == 0 {
+		return "", errKeyringNotFound
+	}
+	return string(out), err
+}
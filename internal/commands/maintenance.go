@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// serverPauseCmd pauses metric collection for a maintenance window
+var serverPauseCmd = &cobra.Command{
+	Use:   "pause <id>",
+	Short: "Pause metric collection for a server",
+	Long: `Pause metric collection for a server during maintenance.
+
+A paused server is shown as "paused" instead of flapping to offline and
+triggering alerts. Resume collection with 'vstats server resume'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setServerState(args[0], ServerStatePaused)
+	},
+}
+
+// serverResumeCmd resumes metric collection after a pause
+var serverResumeCmd = &cobra.Command{
+	Use:   "resume <id>",
+	Short: "Resume metric collection for a server",
+	Long:  `Resume metric collection for a server paused with 'vstats server pause'.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setServerState(args[0], ServerStateActive)
+	},
+}
+
+// setServerState looks up a server and changes its collection state
+func setServerState(serverID, state string) error {
+	if err := requireLogin(); err != nil {
+		return err
+	}
+
+	client := NewClient()
+	server, err := findServerByNameOrID(client, serverID)
+	if err != nil {
+		return err
+	}
+
+	verb := "pause"
+	if state == ServerStateActive {
+		verb = "resume"
+	}
+
+	if dryRun {
+		printDryRun("PUT", "/api/servers/"+server.ID+"/state", map[string]string{"state": state},
+			"would %s metric collection for server '%s'", verb, server.Name)
+		return nil
+	}
+
+	updated, err := client.SetServerState(server.ID, state)
+	if err != nil {
+		return fmt.Errorf("failed to %s server: %w", verb, err)
+	}
+	invalidateServerCache()
+
+	switch outputFmt {
+	case "json":
+		return OutputJSON(updated)
+	case "jsonl":
+		return OutputJSONL(updated)
+	case "yaml":
+		return OutputYAML(updated)
+	case "csv":
+		return OutputCSV(updated)
+	case "template":
+		return OutputTemplate(updated)
+	default:
+		if state == ServerStatePaused {
+			fmt.Printf("✓ Metric collection paused for '%s'\n", server.Name)
+		} else {
+			fmt.Printf("✓ Metric collection resumed for '%s'\n", server.Name)
+		}
+		fmt.Printf("  Status: %s\n", formatStatus(updated.Status))
+	}
+	return nil
+}
+
+func init() {
+	serverCmd.AddCommand(serverPauseCmd)
+	serverCmd.AddCommand(serverResumeCmd)
+}
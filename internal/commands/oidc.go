@@ -0,0 +1,222 @@
+package commands
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// oidcLoginTimeout is how long to wait for the browser to complete the
+// authorization and hit the loopback redirect
+const oidcLoginTimeout = 5 * time.Minute
+
+// oidcDefaultClientID is the client ID registered for the CLI when none is
+// configured
+const oidcDefaultClientID = "vstats-cli"
+
+// oidcDiscovery is the subset of the issuer's discovery document we use
+type oidcDiscovery struct {
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+}
+
+// OIDCTokenRequest exchanges an authorization code for a vStats token
+type OIDCTokenRequest struct {
+	Issuer       string `json:"issuer"`
+	ClientID     string `json:"client_id"`
+	Code         string `json:"code"`
+	CodeVerifier string `json:"code_verifier"`
+	RedirectURI  string `json:"redirect_uri"`
+}
+
+// OIDCTokenResponse is the vStats token issued for an OIDC login
+type OIDCTokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresAt    int64  `json:"expires_at,omitempty"`
+}
+
+// ExchangeOIDCCode exchanges an OIDC authorization code through the cloud's
+// token endpoint
+func (c *Client) ExchangeOIDCCode(req OIDCTokenRequest) (*OIDCTokenResponse, error) {
+	var resp OIDCTokenResponse
+	if err := c.Do("POST", "/api/auth/oidc/token", req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Token == "" {
+		return nil, fmt.Errorf("token endpoint returned no token")
+	}
+	return &resp, nil
+}
+
+// oidcIssuer returns the issuer from --oidc-issuer or the config, or ""
+// when OIDC login isn't configured
+func oidcIssuer(flagValue string) string {
+	issuer := flagValue
+	if issuer == "" {
+		issuer = cfg.OIDCIssuer
+	}
+	return strings.TrimRight(issuer, "/")
+}
+
+// discoverOIDC fetches the issuer's discovery document
+func discoverOIDC(issuer string) (*oidcDiscovery, error) {
+	var doc oidcDiscovery
+	if err := newClientFor(issuer, "").Do("GET", "/.well-known/openid-configuration", nil, &doc); err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC issuer %s: %w", issuer, err)
+	}
+	if doc.AuthorizationEndpoint == "" {
+		return nil, fmt.Errorf("OIDC issuer %s has no authorization_endpoint", issuer)
+	}
+	return &doc, nil
+}
+
+// randomURLString returns n random bytes encoded as unpadded base64url
+func randomURLString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// pkceChallenge derives the S256 code challenge for a verifier
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// runOIDCLogin performs the authorization-code + PKCE flow against issuer
+// using a loopback redirect, and exchanges the code for a vStats token
+func runOIDCLogin(issuer, clientID string) (*OIDCTokenResponse, error) {
+	if clientID == "" {
+		clientID = oidcDefaultClientID
+	}
+
+	doc, err := discoverOIDC(issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, err := randomURLString(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate PKCE verifier: %w", err)
+	}
+	state, err := randomURLString(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate state: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start loopback server: %w", err)
+	}
+	redirectURI := fmt.Sprintf("http://%s/callback", listener.Addr())
+
+	authURL, err := url.Parse(doc.AuthorizationEndpoint)
+	if err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("invalid authorization_endpoint: %w", err)
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", clientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("scope", "openid profile email")
+	query.Set("state", state)
+	query.Set("code_challenge", pkceChallenge(verifier))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	code, err := awaitOIDCCallback(listener, state, authURL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Println("Exchanging authorization code...")
+	return newClientFor(currentCloudURL(), "").ExchangeOIDCCode(OIDCTokenRequest{
+		Issuer:       issuer,
+		ClientID:     clientID,
+		Code:         code,
+		CodeVerifier: verifier,
+		RedirectURI:  redirectURI,
+	})
+}
+
+// awaitOIDCCallback serves the loopback redirect on listener, opens the
+// browser at authURL, and returns the authorization code
+func awaitOIDCCallback(listener net.Listener, state, authURL string) (string, error) {
+	type callback struct {
+		code string
+		err  error
+	}
+	done := make(chan callback, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var result callback
+		switch {
+		case q.Get("state") != state:
+			result.err = fmt.Errorf("OIDC callback state mismatch")
+		case q.Get("error") != "":
+			result.err = fmt.Errorf("authorization failed: %s %s", q.Get("error"), q.Get("error_description"))
+		case q.Get("code") == "":
+			result.err = fmt.Errorf("OIDC callback had no authorization code")
+		default:
+			result.code = q.Get("code")
+		}
+
+		if result.err != nil {
+			http.Error(w, "Login failed: "+html.EscapeString(result.err.Error()), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Login complete. You can close this window and return to the terminal.")
+		}
+		select {
+		case done <- result:
+		default:
+		}
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener)
+	defer server.Close()
+
+	fmt.Println("Opening your browser to sign in. If it doesn't open, visit:")
+	fmt.Println()
+	fmt.Println("  " + authURL)
+	fmt.Println()
+	_ = openBrowser(authURL)
+
+	select {
+	case result := <-done:
+		return result.code, result.err
+	case <-time.After(oidcLoginTimeout):
+		return "", errors.New("timed out waiting for the browser sign-in")
+	case <-commandContext().Done():
+		return "", commandContext().Err()
+	}
+}
+
+// openBrowser opens a URL in the user's default browser
+func openBrowser(target string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", target)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", target)
+	default:
+		cmd = exec.Command("xdg-open", target)
+	}
+	return cmd.Start()
+}
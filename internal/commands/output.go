@@ -1,14 +1,26 @@
 package commands
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
-	"text/tabwriter"
+	"sync"
+	"text/template"
 	"time"
+	"unicode/utf8"
 
+	"golang.org/x/term"
 	"gopkg.in/yaml.v3"
 )
 
@@ -31,17 +43,19 @@ func color(c, text string) string {
 	return c + text + ColorReset
 }
 
-// statusColor returns the appropriate color for a status
+// statusColor returns the appropriate theme color for a status
 func statusColor(status string) string {
 	switch strings.ToLower(status) {
 	case "online", "active", "healthy":
-		return ColorGreen
+		return themeColor(RoleOnline)
 	case "offline", "inactive", "unhealthy":
-		return ColorRed
+		return themeColor(RoleOffline)
 	case "pending", "connecting":
-		return ColorYellow
+		return themeColor(RolePending)
+	case "paused":
+		return themeColor(RoleWarning)
 	default:
-		return ColorGray
+		return themeColor(RoleUnknown)
 	}
 }
 
@@ -54,6 +68,8 @@ func statusIcon(status string) string {
 		return "○"
 	case "pending", "connecting":
 		return "◐"
+	case "paused":
+		return "‖"
 	default:
 		return "?"
 	}
@@ -94,6 +110,18 @@ func formatPercent(value float64) string {
 	return fmt.Sprintf("%.1f%%", value)
 }
 
+// percentBar draws a fixed-width bar filled to a percentage
+func percentBar(value float64, width int) string {
+	filled := int(value/100*float64(width) + 0.5)
+	if filled < 0 {
+		filled = 0
+	}
+	if filled > width {
+		filled = width
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
 // formatDuration formats a duration in human readable format
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
@@ -110,14 +138,55 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%dd %dh", days, hours)
 }
 
-// formatTime formats a time
+// Values accepted by --time-format
+const (
+	TimeFormatRelative = "relative"
+	TimeFormatAbsolute = "absolute"
+	TimeFormatRFC3339  = "rfc3339"
+)
+
+// timeFormat is set by --time-format
+var timeFormat = TimeFormatRelative
+
+// validateTimeFormat checks the --time-format value
+func validateTimeFormat() error {
+	switch timeFormat {
+	case TimeFormatRelative, TimeFormatAbsolute, TimeFormatRFC3339:
+		return nil
+	}
+	return fmt.Errorf("invalid --time-format: %s (use relative, absolute, or rfc3339)", timeFormat)
+}
+
+// formatTime formats a time as a local date and time, or as RFC 3339 with
+// --time-format rfc3339
 func formatTime(t *time.Time) string {
 	if t == nil {
 		return "-"
 	}
+	if timeFormat == TimeFormatRFC3339 {
+		return t.Format(time.RFC3339)
+	}
 	return t.Local().Format("2006-01-02 15:04:05")
 }
 
+// formatTimestamp formats a LAST SEEN or CREATED cell: relative by
+// default, or as formatTime with --time-format absolute or rfc3339
+func formatTimestamp(t *time.Time) string {
+	if timeFormat == TimeFormatRelative {
+		return formatTimeAgo(t)
+	}
+	return formatTime(t)
+}
+
+// formatSampleTime formats a history sample time. Samples are close
+// together, so relative mode shows a compact month-day time instead.
+func formatSampleTime(t time.Time) string {
+	if timeFormat == TimeFormatRelative {
+		return t.Local().Format("01-02 15:04")
+	}
+	return formatTime(&t)
+}
+
 // formatTimeAgo formats a time as relative time
 func formatTimeAgo(t *time.Time) string {
 	if t == nil {
@@ -158,20 +227,343 @@ func (t *Table) AddRow(cells ...string) {
 	t.Rows = append(t.Rows, cells)
 }
 
-// Render renders the table
+// tablePadding is the number of spaces between table columns
+const tablePadding = 2
+
+// ansiPattern matches ANSI SGR escape sequences such as color codes
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// stripANSI removes ANSI escape sequences from s
+func stripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+// visibleWidth returns the number of terminal cells s occupies, ignoring
+// ANSI escape sequences
+func visibleWidth(s string) int {
+	return utf8.RuneCountInString(stripANSI(s))
+}
+
+// Render renders the table. Column widths are measured on the visible text,
+// so colored cells line up with plain ones. With -o markdown the table is
+// rendered as Markdown instead.
 func (t *Table) Render() {
-	w := tabwriter.NewWriter(t.Writer, 0, 0, 2, ' ', 0)
+	if outputFmt == "markdown" {
+		t.RenderMarkdown()
+		return
+	}
+
+	widths := make([]int, len(t.Headers))
+	measure := func(cells []string) {
+		for i, cell := range cells {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := visibleWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	measure(t.Headers)
+	for _, row := range t.Rows {
+		measure(row)
+	}
+
+	if t.Writer == io.Writer(os.Stdout) && !noTruncate {
+		if fd := int(os.Stdout.Fd()); term.IsTerminal(fd) {
+			if width, _, err := term.GetSize(fd); err == nil {
+				widths = fitColumnWidths(widths, width)
+			}
+		}
+	}
+
+	var b strings.Builder
 
 	// Print headers
-	headerLine := strings.Join(t.Headers, "\t")
-	fmt.Fprintln(w, color(ColorCyan, headerLine))
+	fmt.Fprintln(&b, color(themeColor(RoleHeader), alignRow(t.Headers, widths)))
 
 	// Print rows
 	for _, row := range t.Rows {
-		fmt.Fprintln(w, strings.Join(row, "\t"))
+		fmt.Fprintln(&b, alignRow(row, widths))
 	}
+	writePaged(t.Writer, b.String())
+}
 
-	w.Flush()
+// RenderMarkdown renders the table as a pipe-delimited Markdown table, for
+// pasting into issues and chat. Color codes are stripped and pipes in cells
+// are escaped.
+func (t *Table) RenderMarkdown() {
+	fmt.Fprintln(t.Writer, markdownRow(t.Headers))
+	separator := make([]string, len(t.Headers))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	fmt.Fprintln(t.Writer, markdownRow(separator))
+	for _, row := range t.Rows {
+		fmt.Fprintln(t.Writer, markdownRow(row))
+	}
+}
+
+// markdownRow formats cells as one Markdown table row
+func markdownRow(cells []string) string {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = strings.ReplaceAll(stripANSI(cell), "|", `\|`)
+	}
+	return "| " + strings.Join(escaped, " | ") + " |"
+}
+
+// alignRow pads every cell but the last to its column width
+func alignRow(cells []string, widths []int) string {
+	var b strings.Builder
+	for i, cell := range cells {
+		cell = truncateVisible(cell, widths[i])
+		b.WriteString(cell)
+		if i < len(cells)-1 {
+			b.WriteString(strings.Repeat(" ", widths[i]-visibleWidth(cell)+tablePadding))
+		}
+	}
+	return b.String()
+}
+
+// minColumnWidth is the narrowest a column is truncated to
+const minColumnWidth = 4
+
+// fitColumnWidths shrinks column widths so a row fits in total terminal
+// cells. Columns narrower than an even share of the space keep their
+// width; the rest split what remains. Widths never drop below
+// minColumnWidth, so very narrow terminals still wrap.
+func fitColumnWidths(widths []int, total int) []int {
+	avail := total - tablePadding*(len(widths)-1)
+	sum := 0
+	for _, w := range widths {
+		sum += w
+	}
+	if sum <= avail {
+		return widths
+	}
+
+	fitted := make([]int, len(widths))
+	copy(fitted, widths)
+	remaining := make([]int, 0, len(widths))
+	for i := range widths {
+		remaining = append(remaining, i)
+	}
+	// Settle the columns that fit in their share, then re-split the rest
+	for len(remaining) > 0 {
+		share := avail / len(remaining)
+		var wide []int
+		for _, i := range remaining {
+			if widths[i] <= share {
+				avail -= widths[i]
+			} else {
+				wide = append(wide, i)
+			}
+		}
+		if len(wide) == len(remaining) {
+			for n, i := range wide {
+				w := share
+				if n < avail%len(wide) {
+					w++
+				}
+				fitted[i] = max(w, minColumnWidth)
+			}
+			break
+		}
+		remaining = wide
+	}
+	return fitted
+}
+
+// truncateVisible shortens s to width visible cells, ending it with an
+// ellipsis. ANSI escape sequences are kept, and a reset is appended when
+// the cut falls inside a colored span.
+func truncateVisible(s string, width int) string {
+	if visibleWidth(s) <= width {
+		return s
+	}
+
+	var b strings.Builder
+	visible := 0
+	colored := false
+	for len(s) > 0 && visible < width-1 {
+		if loc := ansiPattern.FindStringIndex(s); s[0] == 0x1b && loc != nil && loc[0] == 0 {
+			b.WriteString(s[:loc[1]])
+			colored = s[:loc[1]] != ColorReset
+			s = s[loc[1]:]
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s)
+		b.WriteRune(r)
+		s = s[size:]
+		visible++
+	}
+	b.WriteString("…")
+	if colored {
+		b.WriteString(ColorReset)
+	}
+	return b.String()
+}
+
+// progressBarWidth is the number of cells in a rendered progress bar
+const progressBarWidth = 30
+
+// progressBar renders a single-line progress indicator on stderr when it is a terminal
+type progressBar struct {
+	mu      sync.Mutex
+	total   int
+	done    int
+	enabled bool
+}
+
+// newProgressBar creates a progress bar for total steps
+func newProgressBar(total int) *progressBar {
+	return &progressBar{
+		total:   total,
+		enabled: term.IsTerminal(int(os.Stderr.Fd())),
+	}
+}
+
+// Increment records one finished step and redraws the bar
+func (p *progressBar) Increment() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done++
+	if !p.enabled || p.total == 0 {
+		return
+	}
+	filled := p.done * progressBarWidth / p.total
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+	fmt.Fprintf(os.Stderr, "\r%s %d/%d", bar, p.done, p.total)
+}
+
+// Finish ends the progress line
+func (p *progressBar) Finish() {
+	if p.enabled {
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+// spinnerFrames are drawn in turn by a spinner
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// spinner animates a message on stderr while a long operation runs. When
+// stderr isn't a terminal the message is printed once instead.
+type spinner struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// startSpinner starts a spinner with a message
+func startSpinner(message string) *spinner {
+	s := &spinner{stop: make(chan struct{}), done: make(chan struct{})}
+	if !term.IsTerminal(int(os.Stderr.Fd())) {
+		fmt.Fprintln(os.Stderr, message)
+		close(s.done)
+		return s
+	}
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			fmt.Fprintf(os.Stderr, "\r%s %s", spinnerFrames[i%len(spinnerFrames)], message)
+			select {
+			case <-s.stop:
+				fmt.Fprint(os.Stderr, "\r\033[K")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s
+}
+
+// Stop stops the spinner and clears its line
+func (s *spinner) Stop() {
+	select {
+	case <-s.done:
+	default:
+		close(s.stop)
+		<-s.done
+	}
+}
+
+// noPager is set by --no-pager
+var noPager bool
+
+// noTruncate is set by --no-truncate
+var noTruncate bool
+
+// defaultPager is used when neither VSTATS_PAGER nor PAGER is set. -F quits
+// if the output fits on one screen, -R passes colors through, and -X
+// leaves the output on screen after quitting.
+const defaultPager = "less -FRX"
+
+// pagerCommand returns the pager to run: VSTATS_PAGER, then PAGER, then
+// less -FRX
+func pagerCommand() string {
+	if pager := os.Getenv("VSTATS_PAGER"); pager != "" {
+		return pager
+	}
+	if pager := os.Getenv("PAGER"); pager != "" {
+		return pager
+	}
+	return defaultPager
+}
+
+// writePaged writes output to w, piping it through a pager when w is a
+// terminal's stdout and the output is taller than the terminal. Redirected
+// output, --no-pager, and a missing pager all fall back to writing directly.
+func writePaged(w io.Writer, output string) {
+	fd := int(os.Stdout.Fd())
+	if noPager || w != io.Writer(os.Stdout) || !term.IsTerminal(fd) {
+		fmt.Fprint(w, output)
+		return
+	}
+	_, height, err := term.GetSize(fd)
+	if err != nil || strings.Count(output, "\n") < height {
+		fmt.Fprint(w, output)
+		return
+	}
+
+	args := strings.Fields(pagerCommand())
+	if len(args) == 0 || args[0] == "cat" {
+		fmt.Fprint(w, output)
+		return
+	}
+	pager := exec.Command(args[0], args[1:]...)
+	pager.Stdin = strings.NewReader(output)
+	pager.Stdout = os.Stdout
+	pager.Stderr = os.Stderr
+	if err := pager.Start(); err != nil {
+		fmt.Fprint(w, output)
+		return
+	}
+	_ = pager.Wait()
+}
+
+// dataOut receives structured (json, yaml, csv) output. It is stdout
+// unless --output-fd redirects it to another file descriptor.
+var dataOut io.Writer = os.Stdout
+
+// openOutputFd points dataOut at an inherited file descriptor, checking
+// that it is open for writing
+func openOutputFd(fd int) error {
+	if fd < 0 {
+		return fmt.Errorf("invalid --output-fd %d", fd)
+	}
+	f := os.NewFile(uintptr(fd), fmt.Sprintf("fd%d", fd))
+	if f == nil {
+		return fmt.Errorf("invalid --output-fd %d", fd)
+	}
+	if _, err := f.Write(nil); err != nil {
+		return fmt.Errorf("--output-fd %d is not writable: %w", fd, err)
+	}
+	dataOut = f
+	return nil
 }
 
 // OutputJSON outputs data as JSON
@@ -180,7 +572,33 @@ func OutputJSON(data interface{}) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println(string(output))
+	writePaged(dataOut, string(output)+"\n")
+	return nil
+}
+
+// OutputJSONL outputs data as JSON Lines: each element of a slice is
+// written compactly on its own line, and anything else as one compact line
+func OutputJSONL(items interface{}) error {
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		line, err := json.Marshal(items)
+		if err != nil {
+			return err
+		}
+		writePaged(dataOut, string(line)+"\n")
+		return nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < v.Len(); i++ {
+		line, err := json.Marshal(v.Index(i).Interface())
+		if err != nil {
+			return err
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	writePaged(dataOut, b.String())
 	return nil
 }
 
@@ -190,10 +608,380 @@ func OutputYAML(data interface{}) error {
 	if err != nil {
 		return err
 	}
-	fmt.Print(string(output))
+	writePaged(dataOut, string(output))
+	return nil
+}
+
+// templateText and templateFile are set by --template and --template-file
+var templateText, templateFile string
+
+// outputTemplate is the template -o template renders with, parsed up front
+// by parseOutputTemplate
+var outputTemplate *template.Template
+
+// templateFuncs are the helpers available to -o template. The formatters
+// accept values or pointers and render nil as "-".
+var templateFuncs = template.FuncMap{
+	"formatBytes": func(v interface{}) string {
+		n, ok := templateNumber(v)
+		if !ok {
+			return "-"
+		}
+		return formatBytes(int64(n))
+	},
+	"formatPercent": func(v interface{}) string {
+		n, ok := templateNumber(v)
+		if !ok {
+			return "-"
+		}
+		return formatPercent(n)
+	},
+	"ptrString": func(v interface{}) string {
+		switch s := v.(type) {
+		case string:
+			return s
+		case *string:
+			return ptrString(s)
+		}
+		return "-"
+	},
+}
+
+// templateNumber dereferences a numeric value or pointer for templateFuncs
+func templateNumber(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return 0, false
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// parseOutputTemplate parses --template or --template-file so mistakes are
+// reported before any output. Giving either without -o selects template
+// output; outputChanged reports whether -o was given.
+func parseOutputTemplate(outputChanged bool) error {
+	given := templateText != "" || templateFile != ""
+	if outputFmt != "template" {
+		if !given {
+			return nil
+		}
+		if outputChanged {
+			return fmt.Errorf("--template and --template-file need -o template, not -o %s", outputFmt)
+		}
+		outputFmt = "template"
+	}
+
+	text, name := templateText, "template"
+	switch {
+	case templateText != "" && templateFile != "":
+		return fmt.Errorf("use either --template or --template-file, not both")
+	case templateFile != "":
+		data, err := os.ReadFile(templateFile)
+		if err != nil {
+			return fmt.Errorf("failed to read template file: %w", err)
+		}
+		text, name = string(data), templateFile
+	case templateText == "":
+		return fmt.Errorf("-o template needs --template or --template-file")
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	outputTemplate = tmpl
+	return nil
+}
+
+// OutputTemplate renders data with the -o template template: once per
+// element for a slice, once otherwise. Each rendering ends with a newline.
+// Nothing is written if any rendering fails.
+func OutputTemplate(data interface{}) error {
+	items := []interface{}{data}
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		items = make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).Interface()
+		}
+	}
+
+	var b bytes.Buffer
+	for _, item := range items {
+		start := b.Len()
+		if err := outputTemplate.Execute(&b, item); err != nil {
+			return err
+		}
+		if b.Len() == start || !bytes.HasSuffix(b.Bytes(), []byte("\n")) {
+			b.WriteByte('\n')
+		}
+	}
+	writePaged(dataOut, b.String())
+	return nil
+}
+
+// confirmInput is where confirm reads answers from
+var confirmInput = bufio.NewReader(os.Stdin)
+
+// errNotInteractive is returned by confirm and confirmCount when stdin is
+// not a terminal, so scripts fail fast instead of waiting for an answer
+var errNotInteractive = errors.New("confirmation required but stdin is not a terminal; use --yes to proceed without prompting")
+
+// confirm asks a yes/no question and reads a full line as the answer,
+// re-prompting on anything unrecognized. An empty answer means no. It
+// returns true without asking when --yes is set, errNotInteractive when
+// stdin is not a terminal, and an error when input ends before an answer
+// is given.
+func confirm(prompt string) (bool, error) {
+	if assumeYes {
+		return true, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false, errNotInteractive
+	}
+
+	for {
+		fmt.Printf("%s [y/N] ", prompt)
+		line, err := confirmInput.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		case "":
+			if err == nil {
+				return false, nil
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			fmt.Println()
+			return false, fmt.Errorf("no confirmation received (input closed); use --yes to proceed without prompting")
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to read confirmation: %w", err)
+		}
+		fmt.Println("Please answer yes or no.")
+	}
+}
+
+// confirmCount asks the user to type the number of items about to be
+// affected, a stronger check than y/N for destructive bulk operations. It
+// returns true without asking when --yes is set and errNotInteractive when
+// stdin is not a terminal.
+func confirmCount(count int) (bool, error) {
+	if assumeYes {
+		return true, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false, errNotInteractive
+	}
+
+	fmt.Printf("Type %d to confirm: ", count)
+	line, err := confirmInput.ReadString('\n')
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer == strconv.Itoa(count), nil
+	}
+	if errors.Is(err, io.EOF) {
+		fmt.Println()
+		return false, fmt.Errorf("no confirmation received (input closed); use --yes to proceed without prompting")
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+	return false, nil
+}
+
+// csvColumn is a CSV column mapped to a (possibly nested) struct field
+type csvColumn struct {
+	Name  string
+	Index []int
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// OutputCSV outputs a struct or slice of structs as CSV with a header row.
+// Column names follow the json tags, nested structs are flattened into
+// dotted column names, and nil pointers render as empty cells.
+func OutputCSV(data interface{}) error {
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	var records []reflect.Value
+	elemType := v.Type()
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		elemType = v.Type().Elem()
+		for i := 0; i < v.Len(); i++ {
+			records = append(records, v.Index(i))
+		}
+	} else {
+		records = []reflect.Value{v}
+	}
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+
+	var columns []csvColumn
+	if elemType.Kind() == reflect.Struct && elemType != timeType {
+		columns = csvColumns(elemType, "", nil)
+	} else {
+		columns = []csvColumn{{Name: "value"}}
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.Name
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = csvCell(record, c.Index)
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	writePaged(dataOut, buf.String())
 	return nil
 }
 
+// csvColumns lists the CSV columns for a struct type, flattening nested
+// structs with dotted names and embedded structs without a prefix
+func csvColumns(t reflect.Type, prefix string, index []int) []csvColumn {
+	var columns []csvColumn
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() == reflect.Struct && fieldType != timeType {
+			nested := prefix
+			if !field.Anonymous || name != "" {
+				if name == "" {
+					name = field.Name
+				}
+				nested = prefix + name + "."
+			}
+			columns = append(columns, csvColumns(fieldType, nested, fieldIndex)...)
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		columns = append(columns, csvColumn{Name: prefix + name, Index: fieldIndex})
+	}
+	return columns
+}
+
+// csvCell formats the field at index within v, returning an empty cell
+// when a nil pointer is encountered along the way
+func csvCell(v reflect.Value, index []int) string {
+	for _, i := range index {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return ""
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Struct:
+		if t, ok := v.Interface().(time.Time); ok {
+			return t.Format(time.RFC3339)
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			return ""
+		}
+		if v.Type().Elem().Kind() != reflect.Struct {
+			parts := make([]string, v.Len())
+			for i := range parts {
+				parts[i] = fmt.Sprint(v.Index(i).Interface())
+			}
+			return strings.Join(parts, ";")
+		}
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, fmt.Sprintf("%v=%v", k.Interface(), v.MapIndex(k).Interface()))
+		}
+		sort.Strings(keys)
+		return strings.Join(keys, ";")
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+
+	// Anything else (slices of structs) is embedded as compact JSON
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// printDryRun reports a mutation that was skipped because --dry-run is set,
+// along with the API call that would have been made
+func printDryRun(method, path string, body interface{}, format string, args ...interface{}) {
+	fmt.Printf("(dry-run) "+format+"\n", args...)
+	if body != nil {
+		data, _ := json.Marshal(body)
+		fmt.Printf("  %s %s %s\n", method, path, string(data))
+		return
+	}
+	fmt.Printf("  %s %s\n", method, path)
+}
+
 // ptrString safely dereferences a string pointer
 func ptrString(s *string) string {
 	if s == nil {
@@ -202,6 +990,14 @@ func ptrString(s *string) string {
 	return *s
 }
 
+// orDash returns s, or "-" when it is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 // ptrFloat safely dereferences a float64 pointer
 func ptrFloat(f *float64) string {
 	if f == nil {
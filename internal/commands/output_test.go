@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// renderStatusTable renders a small table with colored status cells
+func renderStatusTable(t *testing.T, colorless bool) string {
+	t.Helper()
+	old := noColor
+	noColor = colorless
+	defer func() { noColor = old }()
+
+	var buf bytes.Buffer
+	table := NewTable("NAME", "STATUS", "CPU")
+	table.Writer = &buf
+	table.AddRow("web-01", formatStatus("online"), "12.5%")
+	table.AddRow("db-primary", formatStatus("offline"), "-")
+	table.AddRow("cache", formatStatus("pending"), "3.0%")
+	table.Render()
+	return buf.String()
+}
+
+func TestTableRenderAlignsColoredCells(t *testing.T) {
+	plain := renderStatusTable(t, true)
+	colored := renderStatusTable(t, false)
+
+	if colored == plain {
+		t.Fatal("colored output has no escape codes")
+	}
+	if got := stripANSI(colored); got != plain {
+		t.Errorf("colored output is aligned differently:\n%s\nwant:\n%s", got, plain)
+	}
+
+	// The last column starts at the same visible offset on every line
+	lines := strings.Split(strings.TrimRight(plain, "\n"), "\n")
+	col := visibleWidth(lines[0][:strings.Index(lines[0], "CPU")])
+	for _, line := range lines[1:] {
+		if idx := visibleWidth(line[:strings.LastIndex(line, " ")+1]); idx != col {
+			t.Errorf("CPU column starts at %d in %q, want %d", idx, line, col)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package commands
+
+import "sync"
+
+// defaultConcurrency bounds the number of parallel API calls and lookups
+const defaultConcurrency = 8
+
+// forEachParallel calls fn for every index in [0, n) using at most limit
+// goroutines at a time, and returns once all calls have finished
+func forEachParallel(n, limit int, fn func(i int)) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
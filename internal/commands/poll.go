@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// errPollTimeout is returned by pollUntil when the timeout elapses
+var errPollTimeout = errors.New("timed out")
+
+// pollUntil calls check immediately and then every interval until it
+// reports done, returns an error, the timeout elapses, or the command is
+// interrupted. A zero timeout polls forever.
+func pollUntil(interval, timeout time.Duration, check func() (bool, error)) error {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	for {
+		done, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		if !deadline.IsZero() && time.Now().Add(interval).After(deadline) {
+			return errPollTimeout
+		}
+		select {
+		case <-commandContext().Done():
+			return commandContext().Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// agentWaitInterval is how often waitForAgent polls the server
+const agentWaitInterval = 3 * time.Second
+
+// waitForAgent polls a server until its agent checks in, i.e. LastSeenAt
+// moves past the value seen on the first poll. The status alone isn't
+// enough: a reinstalled agent's server is often still online from before.
+// A zero timeout waits forever. Progress goes to stderr so structured
+// output on stdout stays clean.
+func waitForAgent(client *Client, id, name string, timeout time.Duration, showSpinner bool) error {
+	var initial *time.Time
+	first := true
+
+	var spin *spinner
+	if showSpinner {
+		spin = startSpinner(fmt.Sprintf("Waiting for the agent on '%s' to connect...", name))
+	}
+	err := pollUntil(agentWaitInterval, timeout, func() (bool, error) {
+		server, err := client.GetServer(id)
+		if err != nil {
+			return false, fmt.Errorf("failed to get server: %w", err)
+		}
+		if first {
+			initial = server.LastSeenAt
+			first = false
+			return false, nil
+		}
+		return server.LastSeenAt != nil && (initial == nil || server.LastSeenAt.After(*initial)), nil
+	})
+	if spin != nil {
+		spin.Stop()
+	}
+
+	if errors.Is(err, errPollTimeout) {
+		return fmt.Errorf("agent on '%s' never checked in within %s - check the agent logs on the host", name, timeout)
+	}
+	if err != nil {
+		return err
+	}
+	if showSpinner {
+		fmt.Fprintf(os.Stderr, "✓ Agent on '%s' connected\n", name)
+	}
+	return nil
+}
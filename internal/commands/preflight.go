@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// preflightCommand checks that the installer can run: curl is present and
+// we are root or have passwordless sudo (the installer runs without a TTY,
+// so sudo can't prompt for a password)
+const preflightCommand = `command -v curl >/dev/null 2>&1 || echo vstats-preflight:no-curl; ` +
+	`if [ "$(id -u)" -eq 0 ] || sudo -n true 2>/dev/null; then echo vstats-preflight:sudo-ok; else echo vstats-preflight:no-sudo; fi`
+
+// sshPreflight verifies connectivity, authentication, and sudo on a host
+// before any cloud resource is created for it, returning a diagnostic
+// error describing what is wrong
+func sshPreflight(sshArgs []string) error {
+	sshPath, err := exec.LookPath("ssh")
+	if err != nil {
+		return fmt.Errorf("ssh not found in PATH. Please install OpenSSH")
+	}
+
+	args := append([]string{"-o", "ConnectTimeout=10"}, sshArgs...)
+	cmd := exec.CommandContext(commandContext(), sshPath, append(args, preflightCommand)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return preflightError(err, stderr.String())
+	}
+
+	out := stdout.String()
+	switch {
+	case strings.Contains(out, "vstats-preflight:no-sudo"):
+		return fmt.Errorf("preflight failed: sudo is not available without a password on the host; log in as root or allow passwordless sudo")
+	case strings.Contains(out, "vstats-preflight:no-curl"):
+		return fmt.Errorf("preflight failed: curl is not installed on the host")
+	case !strings.Contains(out, "vstats-preflight:sudo-ok"):
+		return fmt.Errorf("preflight failed: unexpected response from the host: %s", strings.TrimSpace(out))
+	}
+	return nil
+}
+
+// preflightError classifies a failed ssh run from its stderr
+func preflightError(err error, stderr string) error {
+	detail := strings.TrimSpace(stderr)
+	lower := strings.ToLower(detail)
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 255 {
+		return fmt.Errorf("preflight failed: %w: %s", err, detail)
+	}
+
+	switch {
+	case strings.Contains(lower, "permission denied"), strings.Contains(lower, "too many authentication failures"):
+		return fmt.Errorf("preflight failed: authentication refused (%s); check the user, key, or ssh-agent", detail)
+	case strings.Contains(lower, "host key verification failed"):
+		return fmt.Errorf("preflight failed: host key verification failed; add the host to known_hosts first")
+	case strings.Contains(lower, "could not resolve"),
+		strings.Contains(lower, "connection refused"),
+		strings.Contains(lower, "timed out"),
+		strings.Contains(lower, "no route to host"),
+		strings.Contains(lower, "network is unreachable"):
+		return fmt.Errorf("preflight failed: host unreachable (%s)", detail)
+	default:
+		return fmt.Errorf("preflight failed: ssh could not connect: %s", detail)
+	}
+}
@@ -1,17 +1,39 @@
 package commands
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"time"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var (
-	version   = "dev"
-	cfgFile   string
-	outputFmt string
-	cloudURL  string
-	noColor   bool
+	version     = "dev"
+	cfgFile     string
+	outputFmt   string
+	cloudURL    string
+	noColor     bool
+	dryRun      bool
+	concurrency int
+	quiet       bool
+	jsonErrors  bool
+	assumeYes   bool
+	outputFd    int
+
+	requestTimeout    time.Duration
+	requestTimeoutSet bool
+	debugLevel        int
+
+	// commandDeadline is set by --deadline; cancelDeadline releases the
+	// context that enforces it
+	commandDeadline time.Duration
+	cancelDeadline  context.CancelFunc
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -35,11 +57,169 @@ Examples:
   vstats ssh agent root@server     # Deploy agent via SSH
   vstats ssh web root@server       # Deploy web dashboard via SSH`,
 	SilenceUsage: true,
+	// ReportError prints errors, as JSON with -o json
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if commandDeadline > 0 {
+			// Requests, polling, and SSH all use the command context, so
+			// one deadline bounds the whole command
+			ctx, cancel := context.WithTimeout(cmd.Context(), commandDeadline)
+			cancelDeadline = cancel
+			cmd.SetContext(ctx)
+			cmd.Root().SetContext(ctx)
+		}
+		if err := validateTimeFormat(); err != nil {
+			return err
+		}
+		if err := loadTLSConfig(); err != nil {
+			return err
+		}
+		if err := parseOutputTemplate(cmd.Flags().Changed("output")); err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("output-fd") {
+			return openOutputFd(outputFd)
+		}
+		return nil
+	},
+}
+
+// Process exit codes, following the Nagios plugin convention
+const (
+	ExitOK       = 0
+	ExitWarning  = 1
+	ExitCritical = 2
+	ExitUnknown  = 3
+
+	// ExitDeadline is used when --deadline elapses, as timeout(1) does
+	ExitDeadline = 124
+
+	// ExitInterrupted is the conventional code for a command stopped by Ctrl-C
+	ExitInterrupted = 130
+)
+
+// ExitError is an error that should terminate the process with a specific
+// exit code. Silent errors have already been reported to the user and are
+// not printed again.
+type ExitError struct {
+	Code   int
+	Err    error
+	Silent bool
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the process exit code for an error returned by Execute
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	if errors.Is(err, context.Canceled) {
+		return ExitInterrupted
+	}
+	return 1
+}
+
+// structuredError is the JSON error written to stderr with -o json, and
+// to stdout as well with --json-errors. Code
+// classifies the failure; Status and Message carry the API's response
+// for api_error.
+type structuredError struct {
+	Error    string `json:"error"`
+	Code     string `json:"code"`
+	ExitCode int    `json:"exit_code"`
+	Status   int    `json:"status,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// newStructuredError classifies an error for -o json
+func newStructuredError(err error) structuredError {
+	out := structuredError{Error: err.Error(), Code: "error", ExitCode: ExitCode(err)}
+
+	var respErr *ResponseError
+	var exitErr *ExitError
+	switch {
+	case errors.Is(err, context.Canceled):
+		out.Error, out.Code = "interrupted", "interrupted"
+	case errors.As(err, &respErr):
+		out.Code, out.Status, out.Message = "api_error", respErr.StatusCode, respErr.Message
+		if out.Message == "" {
+			out.Message = respErr.Code
+		}
+	case errors.As(err, &exitErr):
+		switch exitErr.Code {
+		case ExitWarning:
+			out.Code = "warning"
+		case ExitCritical:
+			out.Code = "critical"
+		case ExitUnknown:
+			out.Code = "unknown"
+		case ExitDeadline:
+			out.Code = "deadline_exceeded"
+		}
+	}
+	return out
+}
+
+// ReportError prints an error returned by Execute to stderr, as a JSON
+// object with -o json. With --quiet, -o json and --json-errors it is also
+// written to stdout as JSON so that pipelines reading stdout can tell a
+// failure from an empty result.
+func ReportError(err error) {
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) && exitErr.Silent {
+		return
+	}
+	structured := newStructuredError(err)
+	if outputFmt != "json" {
+		fmt.Fprintln(os.Stderr, structured.Error)
+		return
+	}
+	data, _ := json.Marshal(structured)
+	fmt.Fprintln(os.Stderr, string(data))
+	if quiet && jsonErrors {
+		fmt.Fprintln(dataOut, string(data))
+	}
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
+// The command context is cancelled on Ctrl-C, aborting in-flight requests;
+// a second Ctrl-C kills the process as usual.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	err := rootCmd.ExecuteContext(ctx)
+	if cancelDeadline != nil {
+		defer cancelDeadline()
+		// A failure after the budget ran out is down to the deadline; a
+		// command that finished anyway keeps its success
+		if err != nil && errors.Is(commandContext().Err(), context.DeadlineExceeded) {
+			return &ExitError{Code: ExitDeadline, Err: fmt.Errorf("deadline exceeded: the command did not finish within --deadline %s", commandDeadline)}
+		}
+	}
+	return err
+}
+
+// commandContext returns the context of the running command
+func commandContext() context.Context {
+	if ctx := rootCmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
 }
 
 // SetVersion sets the version string
@@ -51,10 +231,27 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	// Global flags
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.vstats/config.yaml)")
-	rootCmd.PersistentFlags().StringVarP(&outputFmt, "output", "o", "table", "output format (table, json, yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: $XDG_CONFIG_HOME/vstats/config.yaml, or ~/.vstats/config.yaml if it already exists; see 'vstats config path')")
+	rootCmd.PersistentFlags().StringVarP(&outputFmt, "output", "o", "table", "output format (table, wide, json, jsonl, yaml, csv, markdown, template)")
+	rootCmd.PersistentFlags().StringVar(&templateText, "template", "", "Go text/template for -o template, run per item of a list, e.g. '{{.Name}} {{.Status}}'")
+	rootCmd.PersistentFlags().StringVar(&templateFile, "template-file", "", "read the -o template template from this file")
 	rootCmd.PersistentFlags().StringVar(&cloudURL, "cloud-url", "", "vStats Cloud URL (default from config)")
-	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output (also set by NO_COLOR or when stdout is not a terminal)")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "show what would be changed without calling the API")
+	rootCmd.PersistentFlags().IntVar(&concurrency, "concurrency", defaultConcurrency, "maximum number of parallel API calls")
+	rootCmd.PersistentFlags().IntVar(&outputFd, "output-fd", 0, "write structured output (json, yaml, csv) to this file descriptor instead of stdout")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "suppress non-essential output")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "answer yes to all confirmation prompts; without it, prompts fail when stdin is not a terminal")
+	rootCmd.PersistentFlags().DurationVar(&commandDeadline, "deadline", 0, "abort the whole command, including polling, waits, and SSH, after this long, e.g. 30s (exit code 124)")
+	rootCmd.PersistentFlags().DurationVar(&requestTimeout, "request-timeout", 0, "HTTP request timeout, e.g. 2m; 0 disables (default: request_timeout_seconds, or 30s)")
+	rootCmd.PersistentFlags().IntVar(&debugLevel, "debug", 0, "log API requests to stderr; --debug=2 also dumps bodies (Authorization redacted)")
+	rootCmd.PersistentFlags().Lookup("debug").NoOptDefVal = "1"
+	rootCmd.PersistentFlags().BoolVar(&noPager, "no-pager", false, "don't page long output (pager: VSTATS_PAGER, PAGER, or less -FRX)")
+	rootCmd.PersistentFlags().StringVar(&timeFormat, "time-format", TimeFormatRelative, "how to show LAST SEEN/CREATED times (relative, absolute, rfc3339)")
+	rootCmd.PersistentFlags().BoolVar(&noTruncate, "no-truncate", false, "don't shorten table cells to fit the terminal width")
+	rootCmd.PersistentFlags().BoolVar(&insecureTLS, "insecure", false, "skip TLS certificate verification (unsafe; for self-signed test setups)")
+	rootCmd.PersistentFlags().StringVar(&caCertFile, "ca-cert", "", "trust the CA certificates in this PEM file (default from ca_cert)")
+	rootCmd.PersistentFlags().BoolVar(&jsonErrors, "json-errors", false, "with --quiet and -o json, also write errors to stdout as JSON")
 
 	// Add subcommands
 	rootCmd.AddCommand(versionCmd)
@@ -65,6 +262,8 @@ func init() {
 	rootCmd.AddCommand(whoamiCmd)
 	rootCmd.AddCommand(sshCmd)
 	rootCmd.AddCommand(webCmd)
+
+	versionCmd.Flags().Bool("check", false, "check whether a newer release is available")
 }
 
 func initConfig() {
@@ -76,9 +275,27 @@ func initConfig() {
 		}
 	}
 
-	// Override cloud URL from flag
+	requestTimeoutSet = rootCmd.PersistentFlags().Changed("request-timeout")
+	insecureTLSSet = rootCmd.PersistentFlags().Changed("insecure")
+
+	// Override cloud URL: --cloud-url flag, then VSTATS_CLOUD_URL, then config
 	if cloudURL != "" {
-		cfg.CloudURL = cloudURL
+		cloudURLOverride = cloudURL
+	} else if envURL := os.Getenv(envCloudURL); envURL != "" {
+		cloudURLOverride = envURL
+	}
+
+	// Markdown is pasted elsewhere, where escape codes would show up as junk
+	if outputFmt == "markdown" {
+		noColor = true
+	}
+
+	// Follow https://no-color.org and skip escape codes when stdout isn't
+	// a terminal, unless --no-color was given explicitly either way
+	if !rootCmd.PersistentFlags().Changed("no-color") {
+		if os.Getenv("NO_COLOR") != "" || !term.IsTerminal(int(os.Stdout.Fd())) {
+			noColor = true
+		}
 	}
 }
 
@@ -86,8 +303,48 @@ func initConfig() {
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the version number",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("vstats version %s\n", version)
+	Long: `Print the version number.
+
+With --check, the latest release is looked up and compared with this
+version. The result is cached for 24 hours in the config directory, so
+repeated checks don't hit the network. Other commands never check.
+
+Examples:
+  vstats version
+  vstats version --check
+  vstats version --check -o json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if check, _ := cmd.Flags().GetBool("check"); !check {
+			fmt.Printf("vstats version %s\n", version)
+			return nil
+		}
+
+		result, err := checkVersion()
+		if err != nil {
+			return fmt.Errorf("failed to check for updates: %w", err)
+		}
+		switch outputFmt {
+		case "json":
+			return OutputJSON(result)
+		case "jsonl":
+			return OutputJSONL(result)
+		case "yaml":
+			return OutputYAML(result)
+		case "csv":
+			return OutputCSV(result)
+		case "template":
+			return OutputTemplate(result)
+		default:
+			fmt.Printf("vstats version %s\n", version)
+			if result.UpdateAvailable {
+				fmt.Printf("Update available: %s → %s\n", result.Current, result.Latest)
+				if result.ChangelogURL != "" {
+					fmt.Printf("Changelog: %s\n", result.ChangelogURL)
+				}
+			} else {
+				fmt.Printf("Latest release: %s (up to date)\n", result.Latest)
+			}
+		}
+		return nil
 	},
 }
-
@@ -1,8 +1,12 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -29,59 +33,227 @@ var serverListCmd = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"ls"},
 	Short:   "List all servers",
-	Long:    `List all servers associated with your account.`,
+	Long: `List all servers associated with your account.
+
+With -o wide the table also shows the ID, hostname, OS, and agent version
+columns.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := requireLogin(); err != nil {
 			return err
 		}
 
-		client := NewClient()
-		servers, err := client.ListServers()
+		maxAge, swr := getCacheDurations()
+		if cmd.Flags().Changed("max-age") {
+			maxAge, _ = cmd.Flags().GetDuration("max-age")
+		}
+
+		limit, _ := cmd.Flags().GetInt("limit")
+		page, _ := cmd.Flags().GetInt("page")
+		paginated := cmd.Flags().Changed("limit") || cmd.Flags().Changed("page")
+		if paginated && limit < 1 {
+			return fmt.Errorf("--limit must be at least 1 when paging")
+		}
+		if paginated && page < 1 {
+			return fmt.Errorf("--page must be at least 1")
+		}
+
+		resolveDNS, _ := cmd.Flags().GetBool("resolve-dns")
+		showGPU, _ := cmd.Flags().GetBool("gpu")
+		fields, _ := cmd.Flags().GetStringSlice("fields")
+		if len(fields) == 0 {
+			fields = defaultServerColumnKeys(showGPU, resolveDNS)
+		}
+		columns, err := lookupServerColumns(fields)
 		if err != nil {
-			return fmt.Errorf("failed to list servers: %w", err)
+			return err
+		}
+		if outputFmt == "wide" {
+			columns = widenServerColumns(columns)
+		}
+
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		if groupBy != "" {
+			if err := validateGroupKey(groupBy); err != nil {
+				return err
+			}
+		}
+
+		sortKey, _ := cmd.Flags().GetString("sort")
+		reverse, _ := cmd.Flags().GetBool("reverse")
+		if sortKey != "" {
+			if err := validateSortKey(sortKey); err != nil {
+				return err
+			}
+		}
+		listFilter, err := serverFilterFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		// With --fields, a table only needs the attributes it shows; structured
+		// output always gets full servers
+		var apiFields []string
+		switch outputFmt {
+		case "json", "jsonl", "yaml", "csv", "template":
+		default:
+			if cmd.Flags().Changed("fields") {
+				apiFields = serverListAPIFields(cmd, columns)
+			}
+		}
+
+		client := NewClient()
+		var servers []Server
+		var pagination *listPagination
+		if paginated {
+			// Pages come straight from the API; the cache only holds full lists
+			result, err := client.ListServersPage(ServerListOptions{Limit: limit, Page: page, Fields: apiFields})
+			if err != nil {
+				return fmt.Errorf("failed to list servers: %w", err)
+			}
+			servers = result.Servers
+			pagination = newListPagination(page, limit, len(servers), result.Total)
+		} else {
+			list, wait, err := listServersCached(client, maxAge, swr, apiFields)
+			if err != nil {
+				return fmt.Errorf("failed to list servers: %w", err)
+			}
+			defer wait()
+			servers = list.Servers
+
+			if list.Stale {
+				fmt.Fprintf(os.Stderr, "Note: showing cached data from %s ago (stale, refreshing in background)\n",
+					formatDuration(list.Age))
+			}
+		}
+
+		filter := metricFilterFromFlags(cmd)
+		total := len(servers)
+		servers = listFilter.apply(servers)
+		servers, skipped := filter.apply(servers)
+		if skipped > 0 {
+			fmt.Fprintf(os.Stderr, "Note: %d server(s) without metrics were excluded\n", skipped)
+		}
+		if sortKey != "" {
+			sortServers(servers, sortKey, reverse)
+		}
+
+		var groups []serverGroup
+		if groupBy != "" {
+			groups = groupServers(servers, groupBy)
+		}
+
+		var structured interface{} = servers
+		if groups != nil {
+			structured = groupedServerMap(groups)
+		}
+		if pagination != nil {
+			structured = paginatedServerList{Items: structured, Pagination: pagination}
 		}
 
 		switch outputFmt {
 		case "json":
-			return OutputJSON(servers)
+			return OutputJSON(structured)
+		case "jsonl":
+			// One server per line; grouping and pagination metadata don't apply
+			return OutputJSONL(servers)
 		case "yaml":
-			return OutputYAML(servers)
+			return OutputYAML(structured)
+		case "csv":
+			return OutputCSV(servers)
+		case "template":
+			return OutputTemplate(servers)
 		default:
 			if len(servers) == 0 {
+				if total > 0 && (filter.active() || listFilter.active()) {
+					fmt.Println("No servers match the given filters")
+					return nil
+				}
 				fmt.Println("No servers found.")
 				fmt.Println("Use 'vstats server create <name>' to add a server.")
 				return nil
 			}
 
-			table := NewTable("NAME", "STATUS", "CPU", "MEM", "IP", "LAST SEEN")
-			for _, s := range servers {
-				cpu := "-"
-				mem := "-"
-				if s.Metrics != nil {
-					if s.Metrics.CPUUsage != nil {
-						cpu = formatPercent(*s.Metrics.CPUUsage)
-					}
-					if s.Metrics.MemoryTotal != nil && s.Metrics.MemoryUsed != nil && *s.Metrics.MemoryTotal > 0 {
-						memPercent := float64(*s.Metrics.MemoryUsed) / float64(*s.Metrics.MemoryTotal) * 100
-						mem = formatPercent(memPercent)
+			var ptrNames map[string]string
+			if hasServerColumn(columns, "ptr") {
+				ips := make([]string, 0, len(servers))
+				for _, s := range servers {
+					if s.IPAddress != nil {
+						ips = append(ips, *s.IPAddress)
 					}
 				}
+				ptrNames = resolvePTRs(ips)
+			}
 
-				table.AddRow(
-					s.Name,
-					formatStatus(s.Status),
-					cpu,
-					mem,
-					ptrString(s.IPAddress),
-					formatTimeAgo(s.LastSeenAt),
-				)
+			if groups == nil {
+				renderServerTable(servers, columns, ptrNames)
+			} else {
+				for i, g := range groups {
+					if i > 0 {
+						fmt.Println()
+					}
+					fmt.Println(color(themeColor(RoleHeader), fmt.Sprintf("%s: %s (%d)", groupBy, g.Name, len(g.Servers))))
+					renderServerTable(g.Servers, columns, ptrNames)
+				}
+			}
+			if pagination != nil && pagination.Total != nil {
+				fmt.Println()
+				fmt.Println(pagination.summary())
 			}
-			table.Render()
 		}
 		return nil
 	},
 }
 
+// listPagination is the pagination metadata of a paged server list
+type listPagination struct {
+	Page  int  `json:"page" yaml:"page"`
+	Limit int  `json:"limit" yaml:"limit"`
+	Count int  `json:"count" yaml:"count"`
+	Total *int `json:"total,omitempty" yaml:"total,omitempty"`
+}
+
+// paginatedServerList is the structured output of a paged server list
+type paginatedServerList struct {
+	Items      interface{}     `json:"items" yaml:"items"`
+	Pagination *listPagination `json:"pagination" yaml:"pagination"`
+}
+
+// newListPagination builds pagination metadata; total is -1 when unknown
+func newListPagination(page, limit, count, total int) *listPagination {
+	p := &listPagination{Page: page, Limit: limit, Count: count}
+	if total >= 0 {
+		p.Total = &total
+	}
+	return p
+}
+
+// summary describes the range of servers shown, e.g. "Showing 21–40 of 95"
+func (p *listPagination) summary() string {
+	if p.Count == 0 {
+		return fmt.Sprintf("Showing 0 of %d", *p.Total)
+	}
+	first := (p.Page-1)*p.Limit + 1
+	return fmt.Sprintf("Showing %d–%d of %d", first, first+p.Count-1, *p.Total)
+}
+
+// renderServerTable renders the server list table with the given columns
+func renderServerTable(servers []Server, columns []serverColumn, ptrNames map[string]string) {
+	headers := make([]string, len(columns))
+	for i, c := range columns {
+		headers[i] = c.Header
+	}
+
+	table := NewTable(headers...)
+	for _, s := range servers {
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = c.Value(s, ptrNames)
+		}
+		table.AddRow(row...)
+	}
+	table.Render()
+}
+
 // serverCreateCmd creates a new server
 var serverCreateCmd = &cobra.Command{
 	Use:   "create <name>",
@@ -89,7 +261,29 @@ var serverCreateCmd = &cobra.Command{
 	Long: `Create a new server in your account.
 
 After creating the server, you'll receive an agent key that can be used
-to connect an agent to this server.`,
+to connect an agent to this server.
+
+For automated provisioning, keep the agent key out of build logs by
+writing it to a file and suppressing it from the output:
+
+  vstats server create web-01 --key-file ./agent.key --no-output-key
+
+Use --print-install to get the ready-to-run agent install command in the
+same step:
+
+  vstats server create web-01 --print-install --os linux
+
+With --no-output-key, the install command has the placeholder AGENT_KEY
+where the key goes; fill it in before running the command.
+
+Use --wait to block until the agent checks in (exits non-zero if it
+doesn't within --timeout):
+
+  vstats server create web-01 --print-install --wait --timeout 5m
+
+The account's server limit is checked first, so a full plan gets an
+upgrade hint instead of an API error. --force skips the check and lets
+the server decide.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := requireLogin(); err != nil {
@@ -97,25 +291,109 @@ to connect an agent to this server.`,
 		}
 
 		name := args[0]
+		if err := validateServerName(name); err != nil {
+			return err
+		}
+		keyFile, _ := cmd.Flags().GetString("key-file")
+		noOutputKey, _ := cmd.Flags().GetBool("no-output-key")
+		printInstall, _ := cmd.Flags().GetBool("print-install")
+		osType, _ := cmd.Flags().GetString("os")
+		if err := validateInstallOS(osType); err != nil {
+			return err
+		}
+		if dryRun {
+			printDryRun("POST", "/api/servers", map[string]string{"name": name}, "would create server '%s'", name)
+			return nil
+		}
 		client := NewClient()
 
+		if force, _ := cmd.Flags().GetBool("force"); !force {
+			if err := checkServerLimit(client); err != nil {
+				return err
+			}
+		}
+
 		server, err := client.CreateServer(name)
 		if err != nil {
 			return fmt.Errorf("failed to create server: %w", err)
 		}
+		invalidateServerCache()
+
+		if keyFile != "" {
+			if err := os.WriteFile(keyFile, []byte(server.AgentKey+"\n"), 0600); err != nil {
+				return fmt.Errorf("server created but failed to write key file: %w", err)
+			}
+		}
+
+		var install *InstallCommandResponse
+		if printInstall {
+			install, err = client.GetInstallCommand(server.ID, osType)
+			if err != nil {
+				return fmt.Errorf("server created but failed to get install command: %w", err)
+			}
+		}
+
+		output := serverCreateOutput{Server: server, Install: install}
+		if noOutputKey {
+			redacted := *server
+			redacted.AgentKey = redactSecret(server.AgentKey)
+			output.Server = &redacted
+			if install != nil {
+				output.Install = redactInstallCommand(install)
+				install = output.Install
+			}
+		}
 
 		switch outputFmt {
 		case "json":
-			return OutputJSON(server)
+			err = OutputJSON(output)
+		case "jsonl":
+			err = OutputJSONL(output)
 		case "yaml":
-			return OutputYAML(server)
+			err = OutputYAML(output)
+		case "csv":
+			err = OutputCSV(output)
+		case "template":
+			err = OutputTemplate(output)
 		default:
+			if quiet {
+				// Just the ID, for scripts: id=$(vstats -q server create web-01)
+				fmt.Println(server.ID)
+				break
+			}
 			fmt.Printf("✓ Server '%s' created successfully!\n\n", server.Name)
 			fmt.Printf("  ID:        %s\n", server.ID)
-			fmt.Printf("  Agent Key: %s\n", server.AgentKey)
+			switch {
+			case keyFile != "":
+				fmt.Printf("  Agent Key: written to %s\n", keyFile)
+			case !noOutputKey:
+				fmt.Printf("  Agent Key: %s\n", server.AgentKey)
+			}
 			fmt.Println()
-			fmt.Println("To install the agent, run:")
-			fmt.Printf("  vstats server install %s\n", server.ID)
+			if install != nil {
+				fmt.Println("Run this command on your server to install the agent:")
+				fmt.Println()
+				fmt.Printf("  %s\n", install.Command)
+				if noOutputKey {
+					source := fmt.Sprintf("'vstats server key %s'", server.ID)
+					if keyFile != "" {
+						source = keyFile
+					}
+					fmt.Println()
+					fmt.Printf("Replace %s with the agent key from %s before running it.\n", agentKeyPlaceholder, source)
+				}
+			} else {
+				fmt.Println("To install the agent, run:")
+				fmt.Printf("  vstats server install %s\n", server.ID)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		if wait, _ := cmd.Flags().GetBool("wait"); wait {
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			return waitForAgent(client, server.ID, server.Name, timeout, !quiet)
 		}
 		return nil
 	},
@@ -126,13 +404,45 @@ var serverShowCmd = &cobra.Command{
 	Use:     "show <id>",
 	Aliases: []string{"get", "info"},
 	Short:   "Show server details",
-	Long:    `Show detailed information about a specific server.`,
-	Args:    cobra.ExactArgs(1),
+	Long: `Show detailed information about a specific server.
+
+Use --history to add min/avg/max CPU and memory over a recent window
+(e.g. 24h, 7d, or a range preset) below the current metrics.
+--include-history-stats is the same as --history 24h.
+
+Use --follow to watch for an agent upgrade to land: the command polls until
+the agent version changes (or reaches --expect) and exits non-zero if that
+doesn't happen within --timeout.
+
+Examples:
+  vstats server show web-01
+  vstats server show web-01 --history 24h
+  vstats server show web-01 --follow
+  vstats server show web-01 --follow --expect 1.6.0 --timeout 10m`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := requireLogin(); err != nil {
 			return err
 		}
 
+		historyRange, _ := cmd.Flags().GetString("history")
+		if includeStats, _ := cmd.Flags().GetBool("include-history-stats"); includeStats && historyRange == "" {
+			historyRange = "24h"
+		}
+		if historyRange != "" {
+			historyRange = resolveRange(historyRange)
+			if !rangePattern.MatchString(historyRange) {
+				return fmt.Errorf("invalid --history range: %s (use a number followed by m, h, d, or w, e.g. 24h)", historyRange)
+			}
+		}
+
+		follow, _ := cmd.Flags().GetBool("follow")
+		expect, _ := cmd.Flags().GetString("expect")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if (follow || expect != "") && interval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+
 		serverID := args[0]
 		client := NewClient()
 
@@ -142,11 +452,48 @@ var serverShowCmd = &cobra.Command{
 			return err
 		}
 
+		if follow || expect != "" {
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			if server, err = followAgentVersion(client, server, expect, interval, timeout); err != nil {
+				return err
+			}
+		}
+
+		// History stats cost an extra API call, so they are opt-in
+		var stats *HistoryStats
+		if historyRange != "" {
+			history, err := client.GetServerHistory(server.ID, historyRange, "")
+			if err != nil {
+				return fmt.Errorf("failed to get history: %w", err)
+			}
+			stats = summarizeHistory(history)
+		}
+
+		output := serverShowOutput{Server: server, HistoryStats: stats}
+
 		switch outputFmt {
 		case "json":
-			return OutputJSON(server)
+			if annotate, _ := cmd.Flags().GetBool("annotate"); annotate {
+				return OutputJSON(annotatedServerShowOutput{
+					serverShowOutput: output,
+					Metrics:          annotateMetrics(server.Metrics),
+				})
+			}
+			return OutputJSON(output)
+		case "jsonl":
+			if annotate, _ := cmd.Flags().GetBool("annotate"); annotate {
+				return OutputJSONL(annotatedServerShowOutput{
+					serverShowOutput: output,
+					Metrics:          annotateMetrics(server.Metrics),
+				})
+			}
+			return OutputJSONL(output)
 		case "yaml":
-			return OutputYAML(server)
+			return OutputYAML(output)
+		case "csv":
+			return OutputCSV(output)
+		case "template":
+			return OutputTemplate(output)
 		default:
 			fmt.Println("Server Details")
 			fmt.Println("==============")
@@ -159,6 +506,9 @@ var serverShowCmd = &cobra.Command{
 			fmt.Printf("Agent Version: %s\n", ptrString(server.AgentVersion))
 			fmt.Printf("Last Seen:     %s\n", formatTime(server.LastSeenAt))
 			fmt.Printf("Created:       %s\n", formatTime(&server.CreatedAt))
+			if len(server.Tags) > 0 {
+				fmt.Printf("Tags:          %s\n", formatTags(server.Tags))
+			}
 
 			if server.Metrics != nil {
 				fmt.Println()
@@ -176,40 +526,216 @@ var serverShowCmd = &cobra.Command{
 					ptrBytes(server.Metrics.DiskUsed),
 					ptrBytes(server.Metrics.DiskTotal))
 				fmt.Printf("Processes:     %s\n", ptrInt(server.Metrics.ProcessCount))
+				if server.Metrics.NetRxBytes != nil || server.Metrics.NetTxBytes != nil {
+					fmt.Printf("Network:       rx %s / tx %s\n",
+						formatRate(server.Metrics.NetRxBytes),
+						formatRate(server.Metrics.NetTxBytes))
+				}
+				if server.Metrics.CPUTempC != nil {
+					fmt.Printf("CPU Temp:      %s\n", formatTemperature(server.Metrics.CPUTempC))
+				}
+			}
+
+			if stats != nil {
+				heading := "Last " + historyRange
+				fmt.Println()
+				fmt.Println(heading)
+				fmt.Println(strings.Repeat("-", len(heading)))
+				if stats.CPU != nil {
+					fmt.Printf("CPU:           min %s / avg %s / max %s\n",
+						formatPercent(stats.CPU.Min), formatPercent(stats.CPU.Avg), formatPercent(stats.CPU.Max))
+				}
+				if stats.MemoryUsed != nil {
+					fmt.Printf("Memory Used:   min %s / avg %s / max %s\n",
+						formatBytes(int64(stats.MemoryUsed.Min)), formatBytes(int64(stats.MemoryUsed.Avg)),
+						formatBytes(int64(stats.MemoryUsed.Max)))
+				}
+				if stats.CPU == nil && stats.MemoryUsed == nil {
+					fmt.Println("No CPU or memory samples in this window")
+				}
+				fmt.Printf("Offline Gaps:  %d\n", len(stats.OfflineGaps))
+				fmt.Printf("Samples:       %d\n", stats.Samples)
 			}
 		}
 		return nil
 	},
 }
 
+// followAgentVersion polls a server until its agent version changes, or
+// matches expect when given, and returns the updated server. Progress goes
+// to stderr so structured output on stdout stays clean.
+func followAgentVersion(client *Client, server *Server, expect string, interval, timeout time.Duration) (*Server, error) {
+	initial := ptrString(server.AgentVersion)
+	if expect != "" {
+		fmt.Fprintf(os.Stderr, "Waiting for '%s' agent to reach %s (current: %s)...\n", server.Name, expect, initial)
+	} else {
+		fmt.Fprintf(os.Stderr, "Waiting for '%s' agent version to change (current: %s)...\n", server.Name, initial)
+	}
+
+	current := server
+	err := pollUntil(interval, timeout, func() (bool, error) {
+		latest, err := client.GetServer(server.ID)
+		if err != nil {
+			return false, fmt.Errorf("failed to get server: %w", err)
+		}
+		current = latest
+
+		version := ptrString(latest.AgentVersion)
+		if expect != "" {
+			return latest.AgentVersion != nil && compareVersions(version, expect) == 0, nil
+		}
+		return version != initial, nil
+	})
+	if errors.Is(err, errPollTimeout) {
+		return nil, fmt.Errorf("timed out after %s: agent version is still %s", timeout, ptrString(current.AgentVersion))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(os.Stderr, "✓ Agent version is now %s\n\n", ptrString(current.AgentVersion))
+	return current, nil
+}
+
+// installOSTypes are the platforms the agent installer supports
+var installOSTypes = []string{"linux", "darwin", "windows", "docker"}
+
+// validateInstallOS checks an --os value; empty means the server default
+func validateInstallOS(osType string) error {
+	if osType == "" {
+		return nil
+	}
+	for _, t := range installOSTypes {
+		if osType == t {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported --os %q (valid: %s)", osType, strings.Join(installOSTypes, ", "))
+}
+
+// InstallCommands is the install command for every platform at once
+type InstallCommands struct {
+	ServerID  string            `json:"server_id" yaml:"server_id"`
+	Name      string            `json:"name" yaml:"name"`
+	AgentKey  string            `json:"agent_key" yaml:"agent_key"`
+	Default   string            `json:"default" yaml:"default"`
+	Platforms map[string]string `json:"platforms" yaml:"platforms"`
+}
+
+// allInstallCommands collects the install command for each platform. The
+// variants returned by the API are used when present; the rest are
+// fetched per platform, skipping (with a warning) any the API rejects.
+func allInstallCommands(client *Client, server *Server, resp *InstallCommandResponse) *InstallCommands {
+	all := &InstallCommands{
+		ServerID:  server.ID,
+		Name:      server.Name,
+		AgentKey:  resp.AgentKey,
+		Default:   resp.Command,
+		Platforms: make(map[string]string, len(installOSTypes)),
+	}
+	for _, osType := range installOSTypes {
+		if command, ok := resp.Variants[osType]; ok {
+			all.Platforms[osType] = command
+			continue
+		}
+		variant, err := client.GetInstallCommand(server.ID, osType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: no %s install command: %v\n", osType, err)
+			continue
+		}
+		all.Platforms[osType] = variant.Command
+	}
+	return all
+}
+
+// agentKeyPlaceholder stands in for the agent key in an install command
+// printed with --no-output-key. Unlike redactedValue it is a plain shell
+// word, so the command stays well-formed until the key is filled in.
+const agentKeyPlaceholder = "AGENT_KEY"
+
+// redactInstallCommand returns a copy of an install command with the agent
+// key masked in its own field and replaced by agentKeyPlaceholder inside
+// the command line
+func redactInstallCommand(install *InstallCommandResponse) *InstallCommandResponse {
+	redacted := *install
+	if install.AgentKey != "" {
+		redacted.Command = strings.ReplaceAll(install.Command, install.AgentKey, agentKeyPlaceholder)
+		if install.Variants != nil {
+			redacted.Variants = make(map[string]string, len(install.Variants))
+			for osType, command := range install.Variants {
+				redacted.Variants[osType] = strings.ReplaceAll(command, install.AgentKey, agentKeyPlaceholder)
+			}
+		}
+	}
+	redacted.AgentKey = redactSecret(install.AgentKey)
+	return &redacted
+}
+
+// serverCreateOutput is the structured output of server create
+type serverCreateOutput struct {
+	*Server `yaml:",inline"`
+	Install *InstallCommandResponse `json:"install,omitempty" yaml:"install,omitempty"`
+}
+
+// serverShowOutput is the structured output of server show
+type serverShowOutput struct {
+	*Server      `yaml:",inline"`
+	HistoryStats *HistoryStats `json:"history_stats,omitempty" yaml:"history_stats,omitempty"`
+}
+
+// annotatedServerShowOutput is server show JSON output with threshold states
+type annotatedServerShowOutput struct {
+	serverShowOutput
+	Metrics *AnnotatedMetrics `json:"metrics,omitempty"`
+}
+
 // serverDeleteCmd deletes a server
 var serverDeleteCmd = &cobra.Command{
-	Use:     "delete <id>",
+	Use:     "delete <id>...",
 	Aliases: []string{"rm", "remove"},
-	Short:   "Delete a server",
-	Long:    `Delete a server from your account.`,
-	Args:    cobra.ExactArgs(1),
+	Short:   "Delete one or more servers",
+	Long: `Delete servers from your account.
+
+Servers can be given by ID, name, or a name glob pattern (quote it so the
+shell doesn't expand it). When several servers match, they are listed for
+confirmation and deleted in parallel (bounded by --concurrency). Deleting
+more than 50 servers at once requires --i-really-mean-it.
+
+Examples:
+  vstats server delete web-01
+  vstats server delete web-01 web-02 --force
+  vstats server delete 'staging-*'`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := requireLogin(); err != nil {
 			return err
 		}
 
-		serverID := args[0]
 		client := NewClient()
 
-		// Find server first
-		server, err := findServerByNameOrID(client, serverID)
+		// Find servers first
+		servers, err := resolveServerTargets(client, args)
 		if err != nil {
 			return err
 		}
+		if len(servers) > 1 {
+			return runBulkDelete(cmd, client, servers, false)
+		}
+		server := &servers[0]
+
+		if dryRun {
+			printDryRun("DELETE", "/api/servers/"+server.ID, nil, "would delete server '%s'", server.Name)
+			return nil
+		}
 
 		// Confirm deletion
 		force, _ := cmd.Flags().GetBool("force")
 		if !force {
-			fmt.Printf("Are you sure you want to delete server '%s'? [y/N] ", server.Name)
-			var confirm string
-			fmt.Scanln(&confirm)
-			if strings.ToLower(confirm) != "y" && strings.ToLower(confirm) != "yes" {
+			ok, err := confirm(fmt.Sprintf("Are you sure you want to delete server '%s'?", server.Name))
+			if err != nil {
+				return err
+			}
+			if !ok {
 				fmt.Println("Cancelled.")
 				return nil
 			}
@@ -218,6 +744,7 @@ var serverDeleteCmd = &cobra.Command{
 		if err := client.DeleteServer(server.ID); err != nil {
 			return fmt.Errorf("failed to delete server: %w", err)
 		}
+		invalidateServerCache()
 
 		fmt.Printf("✓ Server '%s' deleted\n", server.Name)
 		return nil
@@ -228,57 +755,172 @@ var serverDeleteCmd = &cobra.Command{
 var serverUpdateCmd = &cobra.Command{
 	Use:   "update <id>",
 	Short: "Update server settings",
-	Long:  `Update server name or settings.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Update server name or settings.
+
+Use --from-file to apply many changes at once from a CSV or YAML file.
+CSV files need a header row with a server column (ID, or a name only one
+server has) and any of name, tags, and notes. Tags are key=value pairs separated by semicolons:
+
+  server,name,tags,notes
+  web-01,web-prod-01,env=prod;team=web,
+  db-01,,env=prod,primary database
+
+YAML files contain a list of entries with the same fields. The whole file
+is validated before any change is applied; combine with --dry-run to
+preview the changes.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := requireLogin(); err != nil {
 			return err
 		}
 
+		if fromFile, _ := cmd.Flags().GetString("from-file"); fromFile != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("cannot combine a server argument with --from-file")
+			}
+			return runBulkUpdate(NewClient(), fromFile)
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("requires a server ID or name, or --from-file")
+		}
+
 		serverID := args[0]
 		name, _ := cmd.Flags().GetString("name")
 
 		if name == "" {
 			return fmt.Errorf("no changes specified. Use --name to update the server name")
 		}
+		if err := validateServerName(name); err != nil {
+			return err
+		}
 
-		client := NewClient()
+		return renameServer(NewClient(), serverID, name)
+	},
+}
 
-		// Find server first
-		server, err := findServerByNameOrID(client, serverID)
-		if err != nil {
+// maxServerNameLength is the longest server name the API accepts
+const maxServerNameLength = 64
+
+// serverNamePattern matches the names the API accepts: letters, digits,
+// dots, and dashes, starting with a letter or digit
+var serverNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.-]*$`)
+
+// validateServerName checks a server name before it is sent to the API,
+// so a bad name fails with a clear message instead of an API error
+func validateServerName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("server name must not be empty")
+	case len(name) > maxServerNameLength:
+		return fmt.Errorf("server name %q is too long (at most %d characters)", name, maxServerNameLength)
+	case !serverNamePattern.MatchString(name):
+		return fmt.Errorf("invalid server name %q: use letters, digits, dots, and dashes, starting with a letter or digit", name)
+	}
+	return nil
+}
+
+// serverRenameCmd renames a server
+var serverRenameCmd = &cobra.Command{
+	Use:   "rename <id> <new-name>",
+	Short: "Rename a server",
+	Long: `Rename a server. This is the same as 'vstats server update <id> --name
+<new-name>'.
+
+Examples:
+  vstats server rename web-01 web-prod-01`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireLogin(); err != nil {
 			return err
 		}
 
-		updated, err := client.UpdateServer(server.ID, name)
-		if err != nil {
-			return fmt.Errorf("failed to update server: %w", err)
+		name := strings.TrimSpace(args[1])
+		if err := validateServerName(name); err != nil {
+			return err
 		}
+		return renameServer(NewClient(), args[0], name)
+	},
+}
 
-		switch outputFmt {
-		case "json":
-			return OutputJSON(updated)
-		case "yaml":
-			return OutputYAML(updated)
-		default:
-			fmt.Printf("✓ Server updated: %s\n", updated.Name)
-		}
+// renameServer looks up a server by name or ID and gives it a new name
+func renameServer(client *Client, nameOrID, name string) error {
+	server, err := findServerByNameOrID(client, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		printDryRun("PUT", "/api/servers/"+server.ID, map[string]string{"name": name},
+			"would rename server '%s' to '%s'", server.Name, name)
 		return nil
-	},
+	}
+
+	updated, err := client.UpdateServer(server.ID, name)
+	if err != nil {
+		return fmt.Errorf("failed to update server: %w", err)
+	}
+	invalidateServerCache()
+
+	switch outputFmt {
+	case "json":
+		return OutputJSON(updated)
+	case "jsonl":
+		return OutputJSONL(updated)
+	case "yaml":
+		return OutputYAML(updated)
+	case "csv":
+		return OutputCSV(updated)
+	case "template":
+		return OutputTemplate(updated)
+	default:
+		fmt.Printf("✓ Server updated: %s\n", updated.Name)
+	}
+	return nil
 }
 
 // serverMetricsCmd shows server metrics
 var serverMetricsCmd = &cobra.Command{
 	Use:   "metrics <id>",
 	Short: "View server metrics",
-	Long:  `View the latest metrics for a server.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `View the latest metrics for a server.
+
+Baselines let CI catch performance regressions: save a golden snapshot
+once with --save-baseline, then compare against it with --baseline-file.
+The command exits with code 2 when a metric rises above the baseline by
+more than --tolerance (percentage points for cpu/mem/disk, percent of the
+baseline value for load and process count).
+
+Examples:
+  vstats server metrics web-01
+  vstats server metrics web-01 --baseline-file golden.json --save-baseline
+  vstats server metrics web-01 --baseline-file golden.json --tolerance 10%
+  vstats server metrics web-01 --graph   # add last-hour CPU/memory sparklines
+  vstats server metrics web-01 -o jsonl --flat   # one flat object for a TSDB`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := requireLogin(); err != nil {
 			return err
 		}
 
 		serverID := args[0]
+
+		flat, _ := cmd.Flags().GetBool("flat")
+		if flat && outputFmt != "json" && outputFmt != "jsonl" {
+			return fmt.Errorf("--flat requires -o json or -o jsonl")
+		}
+
+		var until *Condition
+		if expr, _ := cmd.Flags().GetString("loop-until"); expr != "" {
+			var err error
+			if until, err = parseCondition(expr); err != nil {
+				return err
+			}
+		}
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if until != nil && interval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+
 		client := NewClient()
 
 		// Find server first
@@ -287,11 +929,47 @@ var serverMetricsCmd = &cobra.Command{
 			return err
 		}
 
+		if until != nil {
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			return waitForMetricsCondition(client, server, until, interval, timeout)
+		}
+
 		resp, err := client.GetServerMetrics(server.ID)
 		if err != nil {
 			return fmt.Errorf("failed to get metrics: %w", err)
 		}
 
+		if baselinePath, _ := cmd.Flags().GetString("baseline-file"); baselinePath != "" {
+			if resp.Metrics == nil {
+				return fmt.Errorf("no metrics available for this server")
+			}
+			if save, _ := cmd.Flags().GetBool("save-baseline"); save {
+				if err := writeBaseline(baselinePath, server, resp.Metrics); err != nil {
+					return fmt.Errorf("failed to write baseline: %w", err)
+				}
+				fmt.Printf("✓ Baseline for '%s' saved to %s\n", server.Name, baselinePath)
+				return nil
+			}
+			toleranceStr, _ := cmd.Flags().GetString("tolerance")
+			tolerance, err := parseTolerance(toleranceStr)
+			if err != nil {
+				return err
+			}
+			return checkBaseline(baselinePath, server, resp.Metrics, tolerance)
+		}
+
+		if snapshotPath, _ := cmd.Flags().GetString("export-snapshot"); snapshotPath != "" {
+			snapshot, err := buildMetricsSnapshot(client, server, resp.Metrics)
+			if err != nil {
+				return err
+			}
+			if err := writeSnapshot(snapshotPath, snapshot); err != nil {
+				return fmt.Errorf("failed to write snapshot: %w", err)
+			}
+			fmt.Printf("✓ Snapshot for '%s' written to %s\n", server.Name, snapshotPath)
+			return nil
+		}
+
 		if resp.Metrics == nil {
 			fmt.Println("No metrics available for this server.")
 			return nil
@@ -299,41 +977,199 @@ var serverMetricsCmd = &cobra.Command{
 
 		switch outputFmt {
 		case "json":
+			if flat {
+				return OutputJSON(flattenMetrics(server, resp.Metrics, time.Now()))
+			}
+			if annotate, _ := cmd.Flags().GetBool("annotate"); annotate {
+				return OutputJSON(annotateMetrics(resp.Metrics))
+			}
 			return OutputJSON(resp.Metrics)
+		case "jsonl":
+			if flat {
+				return OutputJSONL(flattenMetrics(server, resp.Metrics, time.Now()))
+			}
+			if annotate, _ := cmd.Flags().GetBool("annotate"); annotate {
+				return OutputJSONL(annotateMetrics(resp.Metrics))
+			}
+			return OutputJSONL(resp.Metrics)
 		case "yaml":
 			return OutputYAML(resp.Metrics)
+		case "csv":
+			return OutputCSV(resp.Metrics)
+		case "template":
+			return OutputTemplate(resp.Metrics)
 		default:
-			m := resp.Metrics
-			fmt.Printf("Metrics for %s\n", server.Name)
-			fmt.Println(strings.Repeat("=", 40))
-			fmt.Println()
+			if asTable, _ := cmd.Flags().GetBool("as-table"); asTable {
+				printMetricsTable(server, resp.Metrics)
+			} else {
+				printMetrics(server, resp.Metrics)
+			}
 
-			fmt.Println("CPU")
-			fmt.Printf("  Usage:        %s\n", ptrFloat(m.CPUUsage))
-			fmt.Printf("  Cores:        %s\n", ptrInt(m.CPUCores))
-			fmt.Printf("  Load Avg:     %s / %s / %s\n",
-				ptrFloatRaw(m.LoadAvg1),
-				ptrFloatRaw(m.LoadAvg5),
-				ptrFloatRaw(m.LoadAvg15))
+			if graph, _ := cmd.Flags().GetBool("graph"); graph {
+				history, err := client.GetServerHistory(server.ID, sparklineRange, "")
+				if err != nil {
+					return fmt.Errorf("failed to get history: %w", err)
+				}
+				printMetricsGraphs(history)
+			}
+		}
+		return nil
+	},
+}
 
-			fmt.Println()
-			fmt.Println("Memory")
-			fmt.Printf("  Total:        %s\n", ptrBytes(m.MemoryTotal))
-			fmt.Printf("  Used:         %s\n", ptrBytes(m.MemoryUsed))
-			fmt.Printf("  Free:         %s\n", ptrBytes(m.MemoryFree))
+// printMetrics renders the metrics block shown by server metrics and watch
+func printMetrics(server *Server, m *ServerMetrics) {
+	fmt.Printf("Metrics for %s\n", server.Name)
+	fmt.Println(strings.Repeat("=", 40))
+	fmt.Println()
+
+	fmt.Println("CPU")
+	fmt.Printf("  Usage:        %s\n", ptrFloat(m.CPUUsage))
+	fmt.Printf("  Cores:        %s\n", ptrInt(m.CPUCores))
+	fmt.Printf("  Load Avg:     %s / %s / %s\n",
+		ptrFloatRaw(m.LoadAvg1),
+		ptrFloatRaw(m.LoadAvg5),
+		ptrFloatRaw(m.LoadAvg15))
+	if m.CPUTempC != nil {
+		fmt.Printf("  Temperature:  %s\n", formatTemperature(m.CPUTempC))
+	}
 
-			fmt.Println()
-			fmt.Println("Disk")
-			fmt.Printf("  Total:        %s\n", ptrBytes(m.DiskTotal))
-			fmt.Printf("  Used:         %s\n", ptrBytes(m.DiskUsed))
-			fmt.Printf("  Free:         %s\n", ptrBytes(m.DiskFree))
+	fmt.Println()
+	fmt.Println("Memory")
+	fmt.Printf("  Total:        %s\n", ptrBytes(m.MemoryTotal))
+	fmt.Printf("  Used:         %s\n", ptrBytes(m.MemoryUsed))
+	fmt.Printf("  Free:         %s\n", ptrBytes(m.MemoryFree))
+
+	fmt.Println()
+	fmt.Println("Disk")
+	fmt.Printf("  Total:        %s\n", ptrBytes(m.DiskTotal))
+	fmt.Printf("  Used:         %s\n", ptrBytes(m.DiskUsed))
+	fmt.Printf("  Free:         %s\n", ptrBytes(m.DiskFree))
+
+	fmt.Println()
+	fmt.Println("Processes")
+	fmt.Printf("  Count:        %s\n", ptrInt(m.ProcessCount))
+
+	if m.NetRxBytes != nil || m.NetTxBytes != nil {
+		fmt.Println()
+		fmt.Println("Network")
+		fmt.Printf("  Receive:      %s\n", formatRate(m.NetRxBytes))
+		fmt.Printf("  Transmit:     %s\n", formatRate(m.NetTxBytes))
+	}
 
-			fmt.Println()
-			fmt.Println("Processes")
-			fmt.Printf("  Count:        %s\n", ptrInt(m.ProcessCount))
+	for _, gpu := range m.GPUs {
+		fmt.Println()
+		fmt.Printf("GPU %d (%s)\n", gpu.Index, gpu.Name)
+		util := "-"
+		if gpu.Utilization != nil {
+			util = formatThresholdPercent(MetricGPU, *gpu.Utilization)
 		}
-		return nil
-	},
+		fmt.Printf("  Utilization:  %s\n", util)
+		fmt.Printf("  Memory:       %s / %s\n", ptrBytes(gpu.MemoryUsed), ptrBytes(gpu.MemoryTotal))
+		fmt.Printf("  Temperature:  %s\n", formatTemperature(gpu.Temperature))
+	}
+}
+
+// formatTemperature formats a temperature in degrees Celsius
+func formatTemperature(celsius *float64) string {
+	if celsius == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f°C", *celsius)
+}
+
+// formatRate formats a throughput in bytes per second
+func formatRate(bytesPerSec *int64) string {
+	if bytesPerSec == nil {
+		return "-"
+	}
+	return formatBytes(*bytesPerSec) + "/s"
+}
+
+// metricsBarWidth is the width of usage bars in the metrics table
+const metricsBarWidth = 20
+
+// printMetricsTable renders metrics as a METRIC/VALUE table, with usage
+// bars colored by threshold state
+func printMetricsTable(server *Server, m *ServerMetrics) {
+	usage := func(metric string, value float64) string {
+		bar := color(thresholdColor(thresholdState(metric, value)), percentBar(value, metricsBarWidth))
+		return bar + " " + formatThresholdPercent(metric, value)
+	}
+
+	fmt.Printf("Metrics for %s\n\n", server.Name)
+	table := NewTable("METRIC", "VALUE")
+
+	cpu := "-"
+	if m.CPUUsage != nil {
+		cpu = usage(MetricCPU, *m.CPUUsage)
+	}
+	table.AddRow("CPU Usage", cpu)
+	table.AddRow("CPU Cores", ptrInt(m.CPUCores))
+	table.AddRow("Load Avg", fmt.Sprintf("%s / %s / %s",
+		ptrFloatRaw(m.LoadAvg1), ptrFloatRaw(m.LoadAvg5), ptrFloatRaw(m.LoadAvg15)))
+	if m.CPUTempC != nil {
+		table.AddRow("CPU Temp", formatTemperature(m.CPUTempC))
+	}
+
+	mem := "-"
+	if pct, ok := usagePercent(m.MemoryUsed, m.MemoryTotal); ok {
+		mem = usage(MetricMemory, pct)
+	}
+	table.AddRow("Memory Usage", mem)
+	table.AddRow("Memory Used", fmt.Sprintf("%s / %s", ptrBytes(m.MemoryUsed), ptrBytes(m.MemoryTotal)))
+	table.AddRow("Memory Free", ptrBytes(m.MemoryFree))
+
+	disk := "-"
+	if pct, ok := usagePercent(m.DiskUsed, m.DiskTotal); ok {
+		disk = usage(MetricDisk, pct)
+	}
+	table.AddRow("Disk Usage", disk)
+	table.AddRow("Disk Used", fmt.Sprintf("%s / %s", ptrBytes(m.DiskUsed), ptrBytes(m.DiskTotal)))
+	table.AddRow("Disk Free", ptrBytes(m.DiskFree))
+
+	table.AddRow("Processes", ptrInt(m.ProcessCount))
+	if m.NetRxBytes != nil || m.NetTxBytes != nil {
+		table.AddRow("Network Rx", formatRate(m.NetRxBytes))
+		table.AddRow("Network Tx", formatRate(m.NetTxBytes))
+	}
+
+	for _, gpu := range m.GPUs {
+		label := fmt.Sprintf("GPU %d", gpu.Index)
+		util := "-"
+		if gpu.Utilization != nil {
+			util = usage(MetricGPU, *gpu.Utilization)
+		}
+		table.AddRow(label+" ("+gpu.Name+")", util)
+		table.AddRow(label+" Memory", fmt.Sprintf("%s / %s", ptrBytes(gpu.MemoryUsed), ptrBytes(gpu.MemoryTotal)))
+		table.AddRow(label+" Temp", formatTemperature(gpu.Temperature))
+	}
+	table.Render()
+}
+
+// waitForMetricsCondition polls a server's metrics until the condition
+// holds, reporting progress on stderr
+func waitForMetricsCondition(client *Client, server *Server, until *Condition, interval, timeout time.Duration) error {
+	fmt.Fprintf(os.Stderr, "Waiting for %s on '%s' (timeout %s)...\n", until.Expr, server.Name, timeout)
+
+	err := pollUntil(interval, timeout, func() (bool, error) {
+		resp, err := client.GetServerMetrics(server.ID)
+		if err != nil {
+			return false, fmt.Errorf("failed to get metrics: %w", err)
+		}
+
+		ok := until.Eval(resp.Metrics)
+		state := "waiting"
+		if ok {
+			state = color(themeColor(RoleOnline), "satisfied")
+		}
+		fmt.Fprintf(os.Stderr, "[%s] %s - %s\n", time.Now().Format("15:04:05"), until.Describe(resp.Metrics), state)
+		return ok, nil
+	})
+	if errors.Is(err, errPollTimeout) {
+		return fmt.Errorf("timed out after %s waiting for %s", timeout, until.Expr)
+	}
+	return err
 }
 
 // serverHistoryCmd shows server metrics history
@@ -346,7 +1182,32 @@ Available ranges:
   1h   - Last hour (default)
   24h  - Last 24 hours
   7d   - Last 7 days
-  30d  - Last 30 days`,
+  30d  - Last 30 days
+
+Named presets can be defined in config and used in place of a range:
+  vstats config set ranges.incident 6h
+  vstats server history web-01 --range incident
+
+With --anomalies-only, only data points that deviate from their neighbors
+by more than --anomaly-delta are shown (percentage points for CPU, percent
+change for memory and disk), and quiet stretches are collapsed. It only
+applies to table output.
+
+The server picks a sample resolution for each range by default. Use
+--resolution to request raw agent samples (ranges up to 24h) or a fixed
+bucket size such as 1m, 5m, or 1h.
+
+--metric limits the table to one of cpu, memory, or disk and adds a
+min/avg/max footer for it; JSON, JSON Lines, YAML, and CSV always include
+every metric.
+
+For long-term analysis, -o csv streams collected_at, cpu_usage, memory_used,
+and disk_used rows with RFC3339 timestamps, and -o prometheus writes the
+Prometheus text format with sample timestamps for backfilling.
+
+Examples:
+  vstats server history web-01 --range 24h
+  vstats server history web-01 --metric cpu --resolution 1m`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := requireLogin(); err != nil {
@@ -358,6 +1219,22 @@ Available ranges:
 		if rangeStr == "" {
 			rangeStr = "1h"
 		}
+		rangeStr = resolveRange(rangeStr)
+		resolution, _ := cmd.Flags().GetString("resolution")
+		if err := validateResolution(resolution, rangeStr); err != nil {
+			return err
+		}
+		metric, _ := cmd.Flags().GetString("metric")
+		columns, err := selectHistoryColumns(metric)
+		if err != nil {
+			return err
+		}
+		if anomaliesOnly, _ := cmd.Flags().GetBool("anomalies-only"); anomaliesOnly {
+			switch outputFmt {
+			case "json", "jsonl", "yaml", "csv", "template", "prometheus":
+				return fmt.Errorf("--anomalies-only only applies to table output, not -o %s", outputFmt)
+			}
+		}
 
 		client := NewClient()
 
@@ -367,7 +1244,11 @@ Available ranges:
 			return err
 		}
 
-		history, err := client.GetServerHistory(server.ID, rangeStr)
+		if outputFmt == "csv" {
+			return streamHistoryCSV(client, server, rangeStr, resolution)
+		}
+
+		history, err := client.GetServerHistory(server.ID, rangeStr, resolution)
 		if err != nil {
 			return fmt.Errorf("failed to get history: %w", err)
 		}
@@ -375,8 +1256,15 @@ Available ranges:
 		switch outputFmt {
 		case "json":
 			return OutputJSON(history)
+		case "jsonl":
+			// One data point per line
+			return OutputJSONL(history.Data)
 		case "yaml":
 			return OutputYAML(history)
+		case "template":
+			return OutputTemplate(history)
+		case "prometheus":
+			return writeHistoryPrometheus(server, history)
 		default:
 			fmt.Printf("Metrics History for %s (range: %s)\n", server.Name, history.Range)
 			fmt.Println(strings.Repeat("=", 50))
@@ -386,16 +1274,53 @@ Available ranges:
 				return nil
 			}
 
-			table := NewTable("TIME", "CPU", "MEM USED", "DISK USED")
-			for _, d := range history.Data {
-				table.AddRow(
-					d.CollectedAt.Local().Format("01-02 15:04"),
-					ptrFloat(d.CPUUsage),
-					ptrBytes(d.MemoryUsed),
-					ptrBytes(d.DiskUsed),
-				)
+			anomaliesOnly, _ := cmd.Flags().GetBool("anomalies-only")
+			var anomalies []bool
+			if anomaliesOnly {
+				window, _ := cmd.Flags().GetInt("anomaly-window")
+				delta, _ := cmd.Flags().GetFloat64("anomaly-delta")
+				anomalies = findAnomalies(history.Data, window, delta)
+			}
+
+			headers := []string{"TIME"}
+			for _, c := range columns {
+				headers = append(headers, c.Header)
 			}
+			table := NewTable(headers...)
+			quiet := 0
+			flushQuiet := func() {
+				if quiet > 0 {
+					row := make([]string, len(headers))
+					row[0] = color(ColorGray, fmt.Sprintf("... %d normal points ...", quiet))
+					table.AddRow(row...)
+					quiet = 0
+				}
+			}
+			for i, d := range history.Data {
+				if anomaliesOnly && !anomalies[i] {
+					quiet++
+					continue
+				}
+				flushQuiet()
+				row := []string{formatSampleTime(d.CollectedAt)}
+				for _, c := range columns {
+					row = append(row, c.cell(d))
+				}
+				table.AddRow(row...)
+			}
+			flushQuiet()
 			table.Render()
+
+			if len(columns) == 1 {
+				c := columns[0]
+				fmt.Println()
+				if stats := c.stats(history.Data); stats != nil {
+					fmt.Printf("%s: min %s / avg %s / max %s (%d samples)\n", c.Header,
+						c.Format(stats.Min), c.Format(stats.Avg), c.Format(stats.Max), stats.Count)
+				} else {
+					fmt.Printf("%s: no samples in this range\n", c.Header)
+				}
+			}
 		}
 		return nil
 	},
@@ -405,8 +1330,17 @@ Available ranges:
 var serverInstallCmd = &cobra.Command{
 	Use:   "install <id>",
 	Short: "Get agent installation command",
-	Long:  `Get the command to install the vStats agent on a server.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Get the command to install the vStats agent on a server.
+
+With -o json or -o yaml and no --os, the commands for every supported
+platform (linux, darwin, windows, docker) are returned in one object, so
+automation provisioning a mixed fleet can pick the right one per target.
+
+Examples:
+  vstats server install web-01
+  vstats server install web-01 --os windows
+  vstats server install web-01 -o json    # all platforms`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := requireLogin(); err != nil {
 			return err
@@ -421,16 +1355,35 @@ var serverInstallCmd = &cobra.Command{
 			return err
 		}
 
-		resp, err := client.GetInstallCommand(server.ID)
+		osType, _ := cmd.Flags().GetString("os")
+		if err := validateInstallOS(osType); err != nil {
+			return err
+		}
+
+		resp, err := client.GetInstallCommand(server.ID, osType)
 		if err != nil {
 			return fmt.Errorf("failed to get install command: %w", err)
 		}
 
 		switch outputFmt {
-		case "json":
-			return OutputJSON(resp)
-		case "yaml":
-			return OutputYAML(resp)
+		case "json", "yaml":
+			if osType != "" {
+				if outputFmt == "json" {
+					return OutputJSON(resp)
+				}
+				return OutputYAML(resp)
+			}
+			all := allInstallCommands(client, server, resp)
+			if outputFmt == "json" {
+				return OutputJSON(all)
+			}
+			return OutputYAML(all)
+		case "jsonl":
+			return OutputJSONL(resp)
+		case "csv":
+			return OutputCSV(resp)
+		case "template":
+			return OutputTemplate(resp)
 		default:
 			fmt.Printf("Agent Installation for '%s'\n", server.Name)
 			fmt.Println(strings.Repeat("=", 50))
@@ -467,6 +1420,12 @@ var serverKeyCmd = &cobra.Command{
 		}
 
 		if regenerate {
+			if dryRun {
+				printDryRun("POST", "/api/servers/"+server.ID+"/regenerate-key", nil,
+					"would regenerate agent key for server '%s'", server.Name)
+				return nil
+			}
+
 			resp, err := client.RegenerateAgentKey(server.ID)
 			if err != nil {
 				return fmt.Errorf("failed to regenerate key: %w", err)
@@ -475,8 +1434,14 @@ var serverKeyCmd = &cobra.Command{
 			switch outputFmt {
 			case "json":
 				return OutputJSON(resp)
+			case "jsonl":
+				return OutputJSONL(resp)
 			case "yaml":
 				return OutputYAML(resp)
+			case "csv":
+				return OutputCSV(resp)
+			case "template":
+				return OutputTemplate(resp)
 			default:
 				fmt.Printf("✓ New agent key for '%s':\n", server.Name)
 				fmt.Printf("  %s\n", resp.AgentKey)
@@ -486,9 +1451,15 @@ var serverKeyCmd = &cobra.Command{
 		} else {
 			switch outputFmt {
 			case "json":
-				return OutputJSON(map[string]string{"agent_key": server.AgentKey})
+				return OutputJSON(AgentKeyResponse{AgentKey: server.AgentKey})
+			case "jsonl":
+				return OutputJSONL(AgentKeyResponse{AgentKey: server.AgentKey})
 			case "yaml":
-				return OutputYAML(map[string]string{"agent_key": server.AgentKey})
+				return OutputYAML(AgentKeyResponse{AgentKey: server.AgentKey})
+			case "csv":
+				return OutputCSV(AgentKeyResponse{AgentKey: server.AgentKey})
+			case "template":
+				return OutputTemplate(AgentKeyResponse{AgentKey: server.AgentKey})
 			default:
 				fmt.Printf("Agent key for '%s':\n", server.Name)
 				fmt.Printf("  %s\n", server.AgentKey)
@@ -498,6 +1469,30 @@ var serverKeyCmd = &cobra.Command{
 	},
 }
 
+// checkServerLimit returns an error, after explaining how to upgrade on
+// stderr, when the account already has as many servers as its plan allows.
+// If the account can't be fetched the check is skipped and the API decides.
+func checkServerLimit(client *Client) error {
+	user, err := client.GetCurrentUser()
+	if err != nil || user.ServerLimit <= 0 || user.ServerCount < user.ServerLimit {
+		return nil
+	}
+
+	if !quiet {
+		fmt.Fprintln(os.Stderr, "╔═══════════════════════════════════════════════════╗")
+		fmt.Fprintln(os.Stderr, "║              Server Limit Reached                 ║")
+		fmt.Fprintln(os.Stderr, "╚═══════════════════════════════════════════════════╝")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintf(os.Stderr, "  Your plan: %s\n", user.User.Plan)
+		fmt.Fprintf(os.Stderr, "  Servers: %d / %d\n", user.ServerCount, user.ServerLimit)
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "  Upgrade to Pro for more servers:")
+		fmt.Fprintln(os.Stderr, "    https://vstats.zsoft.cc/pricing")
+		fmt.Fprintln(os.Stderr)
+	}
+	return fmt.Errorf("server limit reached (%d / %d); use --force to try anyway", user.ServerCount, user.ServerLimit)
+}
+
 // findServerByNameOrID finds a server by name or ID
 func findServerByNameOrID(client *Client, nameOrID string) (*Server, error) {
 	// First try to get by ID
@@ -536,15 +1531,63 @@ func init() {
 	serverCmd.AddCommand(serverShowCmd)
 	serverCmd.AddCommand(serverDeleteCmd)
 	serverCmd.AddCommand(serverUpdateCmd)
+	serverCmd.AddCommand(serverRenameCmd)
 	serverCmd.AddCommand(serverMetricsCmd)
 	serverCmd.AddCommand(serverHistoryCmd)
 	serverCmd.AddCommand(serverInstallCmd)
 	serverCmd.AddCommand(serverKeyCmd)
 
 	// Flags
-	serverDeleteCmd.Flags().BoolP("force", "f", false, "force deletion without confirmation")
+	serverListCmd.Flags().String("sort", "", "sort by: "+strings.Join(serverSortKeys, ", "))
+	serverListCmd.Flags().Bool("reverse", false, "reverse the --sort order")
+	serverListCmd.Flags().StringSlice("fields", nil, "table columns to show (and fetch), e.g. name,ip,cpu,last-seen (available: "+strings.Join(serverColumnKeys(), ", ")+")")
+	serverListCmd.Flags().Bool("gpu", false, "show GPU count and utilization columns")
+	serverListCmd.Flags().String("status", "", "only show servers with this status (online, offline, pending, paused)")
+	serverListCmd.Flags().String("filter", "", "only show servers whose name, hostname, or IP contains this text")
+	serverListCmd.Flags().StringArray("tag", nil, "only show servers with this tag, e.g. env=prod (repeatable)")
+	serverListCmd.Flags().Float64("cpu-above", 0, "only show servers with CPU usage above this percentage")
+	serverListCmd.Flags().Float64("mem-above", 0, "only show servers with memory usage above this percentage")
+	serverListCmd.Flags().Float64("disk-above", 0, "only show servers with disk usage above this percentage")
+	serverListCmd.Flags().Bool("resolve-dns", false, "add a PTR column with reverse DNS names for server IPs")
+	serverListCmd.Flags().Int("limit", 0, "number of servers per page (fetches a single page from the API)")
+	serverListCmd.Flags().Int("page", 1, "page number to fetch with --limit")
+	serverListCmd.Flags().String("group-by", "", "group servers into sections by status, os, or tag")
+	serverListCmd.Flags().Duration("max-age", 0, "serve the server list from cache when younger than this (default from cache_ttl)")
+	serverCreateCmd.Flags().String("key-file", "", "write the agent key to this file (mode 0600)")
+	serverCreateCmd.Flags().Bool("no-output-key", false, "never print the agent key")
+	serverCreateCmd.Flags().Bool("print-install", false, "also print the agent install command")
+	serverCreateCmd.Flags().String("os", "", "target OS for --print-install (linux, darwin, windows, docker)")
+	serverCreateCmd.Flags().Bool("wait", false, "wait until the agent connects")
+	serverCreateCmd.Flags().Duration("timeout", 120*time.Second, "give up --wait after this long (0 waits forever)")
+	serverCreateCmd.Flags().Bool("force", false, "skip the server limit check")
+	serverShowCmd.Flags().Bool("include-history-stats", false, "include a summary of the last 24h of metrics (same as --history 24h)")
+	serverShowCmd.Flags().String("history", "", "include min/avg/max CPU and memory over this range, e.g. 24h, 7d, or a preset")
+	serverShowCmd.Flags().Bool("annotate", false, "annotate metrics with threshold states in JSON output")
+	serverShowCmd.Flags().Bool("follow", false, "wait until the agent version changes")
+	serverShowCmd.Flags().String("expect", "", "with --follow, wait for this agent version")
+	serverShowCmd.Flags().Duration("interval", 10*time.Second, "polling interval for --follow")
+	serverShowCmd.Flags().Duration("timeout", 10*time.Minute, "give up --follow after this long")
+	serverDeleteCmd.Flags().BoolP("force", "f", false, "force deletion without confirmation (same as --yes)")
+	serverDeleteCmd.Flags().Bool("i-really-mean-it", false, "allow deleting more than 50 servers at once")
 	serverUpdateCmd.Flags().StringP("name", "n", "", "new server name")
-	serverHistoryCmd.Flags().StringP("range", "r", "1h", "time range (1h, 24h, 7d, 30d)")
+	serverUpdateCmd.Flags().String("from-file", "", "apply changes to many servers from a CSV or YAML file")
+	serverMetricsCmd.Flags().String("loop-until", "", "poll until a condition holds, e.g. 'cpu<20 && mem<80'")
+	serverMetricsCmd.Flags().Duration("interval", 5*time.Second, "polling interval for --loop-until")
+	serverMetricsCmd.Flags().Duration("timeout", 5*time.Minute, "give up --loop-until after this long")
+	serverMetricsCmd.Flags().Bool("annotate", false, "annotate metrics with threshold states in JSON output")
+	serverMetricsCmd.Flags().Bool("flat", false, "with -o json/jsonl, emit one flat object of plain numbers with server_id, server_name, and collected_at")
+	serverMetricsCmd.Flags().Bool("as-table", false, "render metrics as a METRIC/VALUE table with usage bars")
+	serverMetricsCmd.Flags().Bool("graph", false, "show CPU and memory sparklines for the last hour")
+	serverMetricsCmd.Flags().String("baseline-file", "", "compare metrics against a saved baseline file")
+	serverMetricsCmd.Flags().Bool("save-baseline", false, "save current metrics to --baseline-file instead of comparing")
+	serverMetricsCmd.Flags().String("tolerance", "10%", "allowed increase over the baseline")
+	serverMetricsCmd.Flags().String("export-snapshot", "", "write a redacted diagnostic bundle (details, metrics, 24h history) to this file")
+	serverHistoryCmd.Flags().StringP("range", "r", "1h", "time range (1h, 24h, 7d, 30d) or a preset from config")
+	serverHistoryCmd.Flags().String("resolution", "", "sample resolution: raw, or a bucket size such as 1m, 5m, 1h (default chosen by server)")
+	serverHistoryCmd.Flags().String("metric", "all", "metric to show in the table: cpu, memory, disk, or all")
+	serverHistoryCmd.Flags().Bool("anomalies-only", false, "only show data points that deviate from their neighbors")
+	serverHistoryCmd.Flags().Float64("anomaly-delta", 20, "deviation that counts as an anomaly")
+	serverHistoryCmd.Flags().Int("anomaly-window", 5, "number of neighbors on each side used for the rolling mean")
+	serverInstallCmd.Flags().String("os", "", "target OS for the installer (linux, darwin, windows, docker)")
 	serverKeyCmd.Flags().Bool("regenerate", false, "regenerate the agent key")
 }
-
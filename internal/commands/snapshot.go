@@ -0,0 +1,218 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redactedValue replaces secrets in diagnostic output
+const redactedValue = "<redacted>"
+
+// MetricsSnapshot is a portable diagnostic bundle for a single server
+type MetricsSnapshot struct {
+	GeneratedAt time.Time       `json:"generated_at"`
+	CLIVersion  string          `json:"cli_version"`
+	Server      *Server         `json:"server"`
+	Metrics     *ServerMetrics  `json:"metrics"`
+	History     *MetricsHistory `json:"history,omitempty"`
+	Config      snapshotConfig  `json:"config"`
+}
+
+// snapshotConfig is the redacted CLI configuration included in a snapshot
+type snapshotConfig struct {
+	CloudURL string `json:"cloud_url"`
+	Username string `json:"username,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// redactSecret hides a secret value while still showing whether it was set
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// buildMetricsSnapshot gathers a server's details, current metrics, and
+// recent history into a snapshot with all secrets redacted
+func buildMetricsSnapshot(client *Client, server *Server, metrics *ServerMetrics) (*MetricsSnapshot, error) {
+	history, err := client.GetServerHistory(server.ID, "24h", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history: %w", err)
+	}
+
+	redacted := *server
+	redacted.AgentKey = redactSecret(server.AgentKey)
+
+	return &MetricsSnapshot{
+		GeneratedAt: time.Now().UTC(),
+		CLIVersion:  version,
+		Server:      &redacted,
+		Metrics:     metrics,
+		History:     history,
+		Config: snapshotConfig{
+			CloudURL: currentCloudURL(),
+			Username: cfg.Username,
+			Token:    redactSecret(currentToken()),
+		},
+	}, nil
+}
+
+// writeSnapshot writes a snapshot to a JSON file
+func writeSnapshot(path string, snapshot *MetricsSnapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// MetricsBaseline is a golden metrics reference stored in a file
+type MetricsBaseline struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	Server      string         `json:"server"`
+	Metrics     *ServerMetrics `json:"metrics"`
+}
+
+// baselineMetrics are the metrics compared against a baseline. Percentage
+// metrics are compared in percentage points, the rest relative to the
+// baseline value.
+var baselineMetrics = []struct {
+	Name    string
+	Percent bool
+}{
+	{"cpu", true},
+	{"mem", true},
+	{"disk", true},
+	{"load1", false},
+	{"load5", false},
+	{"load15", false},
+	{"processes", false},
+}
+
+// baselineDiff is the comparison of one metric against its baseline
+type baselineDiff struct {
+	Metric     string
+	Baseline   float64
+	Current    float64
+	Change     float64
+	Percent    bool
+	Regression bool
+}
+
+// readBaseline reads a metrics baseline file
+func readBaseline(path string) (*MetricsBaseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var baseline MetricsBaseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("%s is not a valid baseline: %w", path, err)
+	}
+	if baseline.Metrics == nil {
+		return nil, fmt.Errorf("%s has no metrics", path)
+	}
+	return &baseline, nil
+}
+
+// writeBaseline saves metrics as a baseline file
+func writeBaseline(path string, server *Server, metrics *ServerMetrics) error {
+	data, err := json.MarshalIndent(MetricsBaseline{
+		GeneratedAt: time.Now().UTC(),
+		Server:      server.Name,
+		Metrics:     metrics,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// diffBaseline compares current metrics against a baseline. A metric
+// regresses when it rises above the baseline by more than tolerance
+// (percentage points for percentages, percent of the baseline otherwise).
+func diffBaseline(baseline, current *ServerMetrics, tolerance float64) []baselineDiff {
+	var diffs []baselineDiff
+	for _, m := range baselineMetrics {
+		base, ok := conditionMetricValue(m.Name, baseline)
+		if !ok {
+			continue
+		}
+		cur, ok := conditionMetricValue(m.Name, current)
+		if !ok {
+			continue
+		}
+
+		diff := baselineDiff{Metric: m.Name, Baseline: base, Current: cur, Percent: m.Percent}
+		if m.Percent {
+			diff.Change = cur - base
+		} else if base != 0 {
+			diff.Change = (cur - base) / base * 100
+		}
+		diff.Regression = diff.Change > tolerance
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+// parseTolerance parses a tolerance such as "10%" or "10"
+func parseTolerance(s string) (float64, error) {
+	value, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid tolerance: %s (expected a percentage like 10%%)", s)
+	}
+	return value, nil
+}
+
+// checkBaseline prints a diff of current metrics against a baseline and
+// returns an ExitError if any metric regressed beyond the tolerance
+func checkBaseline(path string, server *Server, metrics *ServerMetrics, tolerance float64) error {
+	baseline, err := readBaseline(path)
+	if err != nil {
+		return err
+	}
+
+	diffs := diffBaseline(baseline.Metrics, metrics, tolerance)
+	regressions := 0
+	table := NewTable("METRIC", "BASELINE", "CURRENT", "CHANGE", "RESULT")
+	for _, d := range diffs {
+		change := fmt.Sprintf("%+.1f%%", d.Change)
+		if d.Percent {
+			change = fmt.Sprintf("%+.1f pts", d.Change)
+		}
+		result := color(themeColor(RoleOnline), "ok")
+		if d.Regression {
+			regressions++
+			result = color(themeColor(RoleCritical), "regression")
+		}
+		table.AddRow(d.Metric, formatBaselineValue(d.Baseline, d.Percent),
+			formatBaselineValue(d.Current, d.Percent), change, result)
+	}
+
+	fmt.Printf("Baseline check for %s (tolerance %.1f%%, baseline from %s)\n\n",
+		server.Name, tolerance, formatTime(&baseline.GeneratedAt))
+	table.Render()
+	fmt.Println()
+
+	if regressions > 0 {
+		return &ExitError{
+			Code: ExitCritical,
+			Err:  fmt.Errorf("%d metric(s) regressed beyond the %.1f%% tolerance", regressions, tolerance),
+		}
+	}
+	fmt.Println("✓ All metrics within tolerance")
+	return nil
+}
+
+// formatBaselineValue formats a metric value for the baseline diff table
+func formatBaselineValue(v float64, percent bool) string {
+	if percent {
+		return formatPercent(v)
+	}
+	return fmt.Sprintf("%.2f", v)
+}
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// serverSortKeys lists the keys servers can be sorted by
+var serverSortKeys = []string{"name", "status", "cpu", "mem", "disk", "last-seen"}
+
+// statusRank orders statuses from healthiest to least healthy
+var statusRank = map[string]int{
+	"online":  0,
+	"active":  0,
+	"healthy": 0,
+	"pending": 1,
+	"paused":  1,
+	"offline": 2,
+}
+
+// validateSortKey checks that key is a supported sort key
+func validateSortKey(key string) error {
+	for _, k := range serverSortKeys {
+		if k == key {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid sort key: %s (available: %s)", key, strings.Join(serverSortKeys, ", "))
+}
+
+// sortServers sorts servers in place by key, ascending unless descending is
+// set. Servers without the metric being sorted on are treated as lowest.
+func sortServers(servers []Server, key string, descending bool) {
+	less := serverLessFunc(key)
+	sort.SliceStable(servers, func(i, j int) bool {
+		if descending {
+			return less(servers[j], servers[i])
+		}
+		return less(servers[i], servers[j])
+	})
+}
+
+// serverLessFunc returns the ascending comparison for a sort key
+func serverLessFunc(key string) func(a, b Server) bool {
+	switch key {
+	case "status":
+		return func(a, b Server) bool {
+			return serverStatusRank(a.Status) < serverStatusRank(b.Status)
+		}
+	case "cpu":
+		return func(a, b Server) bool {
+			return metricSortValue(a, "cpu") < metricSortValue(b, "cpu")
+		}
+	case "mem":
+		return func(a, b Server) bool {
+			return metricSortValue(a, "mem") < metricSortValue(b, "mem")
+		}
+	case "disk":
+		return func(a, b Server) bool {
+			return metricSortValue(a, "disk") < metricSortValue(b, "disk")
+		}
+	case "last-seen":
+		return func(a, b Server) bool {
+			if a.LastSeenAt == nil || b.LastSeenAt == nil {
+				return a.LastSeenAt == nil && b.LastSeenAt != nil
+			}
+			return a.LastSeenAt.Before(*b.LastSeenAt)
+		}
+	default:
+		return func(a, b Server) bool {
+			return naturalLess(a.Name, b.Name)
+		}
+	}
+}
+
+// serverStatusRank ranks a status for sorting; unknown statuses sort last
+func serverStatusRank(status string) int {
+	if rank, ok := statusRank[strings.ToLower(status)]; ok {
+		return rank
+	}
+	return len(statusRank)
+}
+
+// metricSortValue returns a metric for sorting, or -1 when it is missing
+// so that servers without metrics sort lowest
+func metricSortValue(s Server, metric string) float64 {
+	if value, ok := conditionMetricValue(metric, s.Metrics); ok {
+		return value
+	}
+	return -1
+}
+
+// naturalLess compares strings case-insensitively, treating runs of digits
+// as numbers so that "web-2" sorts before "web-10"
+func naturalLess(a, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	for a != "" && b != "" {
+		ra, rb := rune(a[0]), rune(b[0])
+		if unicode.IsDigit(ra) && unicode.IsDigit(rb) {
+			na, restA := splitDigits(a)
+			nb, restB := splitDigits(b)
+			// Compare numerically: longer digit runs (ignoring leading zeros) are larger
+			ta, tb := strings.TrimLeft(na, "0"), strings.TrimLeft(nb, "0")
+			if len(ta) != len(tb) {
+				return len(ta) < len(tb)
+			}
+			if ta != tb {
+				return ta < tb
+			}
+			a, b = restA, restB
+			continue
+		}
+		if ra != rb {
+			return ra < rb
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+// splitDigits splits a leading run of ASCII digits from s
+func splitDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return s[:i], s[i:]
+}
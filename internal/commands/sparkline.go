@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sparkBlocks are the sparkline levels from lowest to highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparklineWidth is the most characters a sparkline is drawn with; longer
+// series are averaged down to fit
+const sparklineWidth = 60
+
+// sparklineRange is the history window shown by server metrics --graph
+const sparklineRange = "1h"
+
+// sparkline renders values as Unicode blocks scaled to their own min/max.
+// Nil values are drawn as gaps. It returns the line and the min/max seen.
+func sparkline(values []*float64) (line string, lo, hi float64, ok bool) {
+	values = downsample(values, sparklineWidth)
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		if !ok || *v < lo {
+			lo = *v
+		}
+		if !ok || *v > hi {
+			hi = *v
+		}
+		ok = true
+	}
+	if !ok {
+		return "", 0, 0, false
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		if v == nil {
+			b.WriteRune(' ')
+			continue
+		}
+		level := 0
+		if hi > lo {
+			level = int((*v - lo) / (hi - lo) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String(), lo, hi, true
+}
+
+// downsample averages values into at most width buckets, leaving a bucket
+// nil when it has no data
+func downsample(values []*float64, width int) []*float64 {
+	if len(values) <= width {
+		return values
+	}
+
+	out := make([]*float64, width)
+	for i := range out {
+		start := i * len(values) / width
+		end := (i + 1) * len(values) / width
+		var sum float64
+		n := 0
+		for _, v := range values[start:end] {
+			if v != nil {
+				sum += *v
+				n++
+			}
+		}
+		if n > 0 {
+			avg := sum / float64(n)
+			out[i] = &avg
+		}
+	}
+	return out
+}
+
+// printMetricsGraphs renders CPU and memory sparklines for a history window
+func printMetricsGraphs(history *MetricsHistory) {
+	cpu := make([]*float64, len(history.Data))
+	mem := make([]*float64, len(history.Data))
+	for i, d := range history.Data {
+		cpu[i] = d.CPUUsage
+		if d.MemoryUsed != nil {
+			used := float64(*d.MemoryUsed)
+			mem[i] = &used
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("Last %s\n", sparklineRange)
+	if line, lo, hi, ok := sparkline(cpu); ok {
+		fmt.Printf("  CPU:          %s  %s–%s\n", line, formatPercent(lo), formatPercent(hi))
+	} else {
+		fmt.Println("  CPU:          no data")
+	}
+	if line, lo, hi, ok := sparkline(mem); ok {
+		fmt.Printf("  Memory Used:  %s  %s–%s\n", line, formatBytes(int64(lo)), formatBytes(int64(hi)))
+	} else {
+		fmt.Println("  Memory Used:  no data")
+	}
+}
@@ -1,10 +1,14 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -15,8 +19,34 @@ var (
 	sshPort     int
 	sshKey      string
 	sshPassword string
+	sshJump     string
+	sshOptions  []string
 )
 
+var (
+	// jumpHostPattern matches one [user@]host[:port] hop of a -J spec
+	jumpHostPattern = regexp.MustCompile(`^([A-Za-z0-9._-]+@)?(\[[0-9A-Fa-f:.]+\]|[A-Za-z0-9._-]+)(:[0-9]{1,5})?$`)
+	// sshOptionPattern matches a Key=Value ssh option
+	sshOptionPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*=.+$`)
+)
+
+// validateSSHFlags checks --jump and --ssh-option before ssh is invoked
+func validateSSHFlags() error {
+	if sshJump != "" {
+		for _, hop := range strings.Split(sshJump, ",") {
+			if !jumpHostPattern.MatchString(hop) {
+				return fmt.Errorf("invalid --jump %q (use user@host[:port], comma-separated for several hops)", hop)
+			}
+		}
+	}
+	for _, opt := range sshOptions {
+		if !sshOptionPattern.MatchString(opt) {
+			return fmt.Errorf("invalid --ssh-option %q (use Key=Value, e.g. StrictHostKeyChecking=accept-new)", opt)
+		}
+	}
+	return nil
+}
+
 // sshCmd represents the ssh command group
 var sshCmd = &cobra.Command{
 	Use:   "ssh",
@@ -34,12 +64,12 @@ Examples:
 
 // sshAgentCmd deploys agent to a host via SSH
 var sshAgentCmd = &cobra.Command{
-	Use:   "agent <host>",
+	Use:   "agent <host>...",
 	Short: "Deploy vStats agent via SSH",
 	Long: `Deploy the vStats agent to a remote server via SSH.
 
 This command will:
-  1. Connect to the server via SSH
+  1. Check SSH access, sudo, and curl on the server
   2. Create a new server in vStats Cloud (or use existing)
   3. Download and install the vStats agent
   4. Start the agent service
@@ -51,30 +81,99 @@ Examples:
   vstats ssh agent myserver                    # Use SSH config alias
   vstats ssh agent server.com -u admin
   vstats ssh agent server.com --name "Prod-01"
-  vstats ssh agent server.com --server existing-server-id`,
-	Args: cobra.ExactArgs(1),
+  vstats ssh agent server.com --server existing-server-id
+  vstats ssh agent 10.0.0.5 --jump admin@bastion.example.com
+
+Several hosts (or a --hosts-file with one host per line) are deployed
+concurrently, creating one server per host named after it. SSH runs in
+batch mode, so hosts must accept key-based authentication. A summary is
+printed at the end and the command fails if any host failed:
+  vstats ssh agent web-01 web-02 web-03
+  vstats ssh agent --hosts-file servers.txt --parallel 8
+
+With --wait, the command also blocks until each agent checks in and fails
+if one doesn't within --timeout.
+
+With --dry-run, the rendered install command and the exact ssh invocation
+are printed (with the token masked) without creating a server or
+connecting:
+  vstats ssh agent web-01 --dry-run`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := requireLogin(); err != nil {
 			return err
 		}
+		if err := validateSSHFlags(); err != nil {
+			return err
+		}
 
-		hostArg := args[0]
 		serverName, _ := cmd.Flags().GetString("name")
 		existingServerID, _ := cmd.Flags().GetString("server")
+		hostsFile, _ := cmd.Flags().GetString("hosts-file")
+		wait, _ := cmd.Flags().GetBool("wait")
+		waitTimeout, _ := cmd.Flags().GetDuration("timeout")
 
-		// Parse host (user@host or just host from ssh config)
-		user, host := parseSSHHost(hostArg)
-		if sshUser != "" {
-			user = sshUser
+		hosts := args
+		if hostsFile != "" {
+			fileHosts, err := readHostsFile(hostsFile)
+			if err != nil {
+				return err
+			}
+			hosts = append(hosts, fileHosts...)
 		}
-		if user == "" {
-			user = "root"
+		if len(hosts) == 0 {
+			return fmt.Errorf("no hosts given; pass one or more hosts or --hosts-file")
 		}
+		if len(hosts) > 1 {
+			if serverName != "" || existingServerID != "" {
+				return fmt.Errorf("--name and --server can only be used with a single host")
+			}
+			// Each host becomes a server named after it
+			for _, h := range hosts {
+				_, host := resolveSSHTarget(h)
+				if err := validateServerName(host); err != nil {
+					return fmt.Errorf("%s: %w", h, err)
+				}
+			}
+			if dryRun {
+				for _, h := range hosts {
+					printAgentDryRun(h, "", "", true)
+				}
+				return nil
+			}
+			parallel, _ := cmd.Flags().GetInt("parallel")
+			return deployAgents(NewClient(), hosts, parallel, wait, waitTimeout)
+		}
+
+		hostArg := hosts[0]
+		user, host := resolveSSHTarget(hostArg)
 
 		// Default server name to hostname
-		if serverName == "" {
+		nameFromHost := serverName == ""
+		if nameFromHost {
 			serverName = host
 		}
+		if existingServerID == "" {
+			if err := validateServerName(serverName); err != nil {
+				if nameFromHost {
+					return fmt.Errorf("%w; pass --name to choose one", err)
+				}
+				return err
+			}
+		}
+
+		if dryRun {
+			printAgentDryRun(hostArg, serverName, existingServerID, false)
+			return nil
+		}
+
+		// Check access before creating anything in the cloud
+		sshArgs := buildSSHArgs(user, host)
+		if !quiet {
+			fmt.Printf("Checking SSH access to %s...\n", hostArg)
+		}
+		if err := sshPreflight(sshArgs); err != nil {
+			return err
+		}
 
 		client := NewClient()
 
@@ -89,54 +188,58 @@ Examples:
 			}
 			serverID = server.ID
 			agentKey = server.AgentKey
-			fmt.Printf("Using existing server: %s\n", server.Name)
+			if !quiet {
+				fmt.Printf("Using existing server: %s\n", server.Name)
+			}
 		} else {
-			fmt.Printf("Creating server '%s'...\n", serverName)
+			if !quiet {
+				fmt.Printf("Creating server '%s'...\n", serverName)
+			}
 			server, err := client.CreateServer(serverName)
 			if err != nil {
 				return fmt.Errorf("failed to create server: %w", err)
 			}
+			invalidateServerCache()
 			serverID = server.ID
 			agentKey = server.AgentKey
-			fmt.Printf("✓ Server created: %s\n", server.ID)
-		}
-
-		// Build SSH command
-		sshArgs := buildSSHArgs(user, host)
-
-		// Get the cloud URL
-		cloudURL := cfg.CloudURL
-		if cloudURL == "" {
-			cloudURL = "https://api.vstats.zsoft.cc"
+			if !quiet {
+				fmt.Printf("✓ Server created: %s\n", server.ID)
+			}
 		}
 
 		// Generate install command
-		installCmd := fmt.Sprintf(
-			`curl -fsSL https://vstats.zsoft.cc/agent.sh | sudo bash -s -- --server "%s" --token "%s" --name "%s"`,
-			cloudURL, cfg.Token, serverName,
-		)
+		installCmd := agentInstallCommand(serverName)
 
-		fmt.Printf("\nConnecting to %s...\n", hostArg)
-		fmt.Println("Deploying vStats agent...")
-		fmt.Println()
+		if !quiet {
+			fmt.Printf("\nConnecting to %s...\n", hostArg)
+			fmt.Println("Deploying vStats agent...")
+			fmt.Println()
+		}
 
 		// Execute via SSH
 		if err := runSSHCommand(sshArgs, installCmd); err != nil {
 			return fmt.Errorf("deployment failed: %w", err)
 		}
 
-		fmt.Println()
-		fmt.Println("╔═══════════════════════════════════════════════════╗")
-		fmt.Println("║        Agent Deployed Successfully!               ║")
-		fmt.Println("╚═══════════════════════════════════════════════════╝")
-		fmt.Println()
-		fmt.Printf("  Server ID:  %s\n", serverID)
-		fmt.Printf("  Agent Key:  %s\n", agentKey)
-		fmt.Println()
-		fmt.Println("  View metrics:")
-		fmt.Printf("    vstats server metrics %s\n", serverName)
-		fmt.Println()
+		if quiet {
+			fmt.Println(serverID)
+		} else {
+			fmt.Println()
+			fmt.Println("╔═══════════════════════════════════════════════════╗")
+			fmt.Println("║        Agent Deployed Successfully!               ║")
+			fmt.Println("╚═══════════════════════════════════════════════════╝")
+			fmt.Println()
+			fmt.Printf("  Server ID:  %s\n", serverID)
+			fmt.Printf("  Agent Key:  %s\n", agentKey)
+			fmt.Println()
+			fmt.Println("  View metrics:")
+			fmt.Printf("    vstats server metrics %s\n", serverName)
+			fmt.Println()
+		}
 
+		if wait {
+			return waitForAgent(client, serverID, serverName, waitTimeout, !quiet)
+		}
 		return nil
 	},
 }
@@ -156,18 +259,29 @@ Examples:
   vstats ssh web root@192.168.1.1
   vstats ssh web myserver --name "Home Dashboard"
   vstats ssh web server.com --port 8080
-  vstats ssh web server.com --ssl --domain dashboard.example.com`,
+  vstats ssh web server.com --ssl --domain dashboard.example.com
+  vstats ssh web server.com --version 1.4.2     # Pin or roll back a release`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := requireLogin(); err != nil {
 			return err
 		}
+		if err := validateSSHFlags(); err != nil {
+			return err
+		}
 
 		hostArg := args[0]
 		webName, _ := cmd.Flags().GetString("name")
 		webPort, _ := cmd.Flags().GetInt("web-port")
 		domain, _ := cmd.Flags().GetString("domain")
 		enableSSL, _ := cmd.Flags().GetBool("ssl")
+		webVersion, _ := cmd.Flags().GetString("version")
+		if webVersion != "" {
+			var err error
+			if webVersion, err = normalizeReleaseVersion(webVersion); err != nil {
+				return err
+			}
+		}
 
 		// Check user plan
 		client := NewClient()
@@ -177,27 +291,23 @@ Examples:
 		}
 
 		if !plan.IsPro && plan.CurrentCount >= plan.MaxWebApps {
-			fmt.Println("╔═══════════════════════════════════════════════════╗")
-			fmt.Println("║           Web Instance Limit Reached              ║")
-			fmt.Println("╚═══════════════════════════════════════════════════╝")
-			fmt.Println()
-			fmt.Printf("  Your plan: %s\n", plan.Plan)
-			fmt.Printf("  Web instances: %d / %d\n", plan.CurrentCount, plan.MaxWebApps)
-			fmt.Println()
-			fmt.Println("  Upgrade to Pro for unlimited web instances:")
-			fmt.Println("    https://vstats.zsoft.cc/pricing")
-			fmt.Println()
+			if !quiet {
+				fmt.Println("╔═══════════════════════════════════════════════════╗")
+				fmt.Println("║           Web Instance Limit Reached              ║")
+				fmt.Println("╚═══════════════════════════════════════════════════╝")
+				fmt.Println()
+				fmt.Printf("  Your plan: %s\n", plan.Plan)
+				fmt.Printf("  Web instances: %d / %d\n", plan.CurrentCount, plan.MaxWebApps)
+				fmt.Println()
+				fmt.Println("  Upgrade to Pro for unlimited web instances:")
+				fmt.Println("    https://vstats.zsoft.cc/pricing")
+				fmt.Println()
+			}
 			return fmt.Errorf("web instance limit reached")
 		}
 
 		// Parse host
-		user, host := parseSSHHost(hostArg)
-		if sshUser != "" {
-			user = sshUser
-		}
-		if user == "" {
-			user = "root"
-		}
+		user, host := resolveSSHTarget(hostArg)
 
 		// Defaults
 		if webName == "" {
@@ -207,49 +317,60 @@ Examples:
 			webPort = 3001
 		}
 
-		fmt.Printf("Deploying web dashboard '%s'...\n", webName)
-		fmt.Printf("  Host: %s\n", hostArg)
-		fmt.Printf("  Port: %d\n", webPort)
-		if domain != "" {
-			fmt.Printf("  Domain: %s\n", domain)
+		newInstance := &WebInstance{
+			Name:    webName,
+			Host:    host,
+			Port:    webPort,
+			URL:     buildWebURL(host, webPort, domain, enableSSL),
+			Version: webVersion,
 		}
-		if enableSSL {
-			fmt.Println("  SSL: enabled")
+
+		installCmd := webInstallCommand(webPort, domain, enableSSL, webVersion)
+
+		if dryRun {
+			printDryRun("POST", "/api/web/instances", newInstance,
+				"would register web dashboard '%s' and install it on %s", webName, hostArg)
+			fmt.Printf("  remote command: %s\n", redactToken(installCmd))
+			fmt.Printf("  ssh argv: %s\n", redactToken(formatSSHInvocation(buildSSHArgs(user, host), installCmd)))
+			return nil
 		}
-		fmt.Println()
 
-		// Register web instance in cloud
-		instance, err := client.RegisterWebInstance(&WebInstance{
-			Name: webName,
-			Host: host,
-			Port: webPort,
-			URL:  buildWebURL(host, webPort, domain, enableSSL),
-		})
-		if err != nil {
-			return fmt.Errorf("failed to register web instance: %w", err)
+		if !quiet {
+			fmt.Printf("Deploying web dashboard '%s'...\n", webName)
+			fmt.Printf("  Host: %s\n", hostArg)
+			fmt.Printf("  Port: %d\n", webPort)
+			if webVersion != "" {
+				fmt.Printf("  Version: %s\n", webVersion)
+			}
+			if domain != "" {
+				fmt.Printf("  Domain: %s\n", domain)
+			}
+			if enableSSL {
+				fmt.Println("  SSL: enabled")
+			}
+			fmt.Println()
 		}
 
-		// Build SSH command
+		// Check access before registering anything in the cloud
 		sshArgs := buildSSHArgs(user, host)
-
-		// Get cloud URL
-		cloudURL := cfg.CloudURL
-		if cloudURL == "" {
-			cloudURL = "https://api.vstats.zsoft.cc"
+		if !quiet {
+			fmt.Printf("Checking SSH access to %s...\n", hostArg)
+		}
+		if err := sshPreflight(sshArgs); err != nil {
+			return err
 		}
 
-		// Generate install command
-		installCmd := fmt.Sprintf(
-			`curl -fsSL https://vstats.zsoft.cc/install.sh | sudo bash -s -- --cloud-mode --cloud-url "%s" --cloud-token "%s" --port %d`,
-			cloudURL, cfg.Token, webPort,
-		)
-		if enableSSL && domain != "" {
-			installCmd += fmt.Sprintf(` --ssl --domain "%s"`, domain)
+		// Register web instance in cloud
+		instance, err := client.RegisterWebInstance(newInstance)
+		if err != nil {
+			return fmt.Errorf("failed to register web instance: %w", err)
 		}
 
-		fmt.Printf("Connecting to %s...\n", hostArg)
-		fmt.Println("Installing vStats web dashboard...")
-		fmt.Println()
+		if !quiet {
+			fmt.Printf("Connecting to %s...\n", hostArg)
+			fmt.Println("Installing vStats web dashboard...")
+			fmt.Println()
+		}
 
 		// Execute via SSH
 		if err := runSSHCommand(sshArgs, installCmd); err != nil {
@@ -262,6 +383,10 @@ Examples:
 		instance.Status = "online"
 		_ = client.UpdateWebInstance(instance)
 
+		if quiet {
+			fmt.Println(instance.ID)
+			return nil
+		}
 		fmt.Println()
 		fmt.Println("╔═══════════════════════════════════════════════════╗")
 		fmt.Println("║       Web Dashboard Deployed Successfully!        ║")
@@ -288,6 +413,196 @@ func parseSSHHost(hostArg string) (string, string) {
 	return "", hostArg
 }
 
+// resolveSSHTarget parses a host argument and applies the --user flag and
+// the root default, returning (user, host)
+func resolveSSHTarget(hostArg string) (string, string) {
+	user, host := parseSSHHost(hostArg)
+	if sshUser != "" {
+		user = sshUser
+	}
+	if user == "" {
+		user = "root"
+	}
+	return user, host
+}
+
+// webInstallCommand builds the remote web dashboard install command. An
+// empty version installs the latest release.
+func webInstallCommand(port int, domain string, ssl bool, version string) string {
+	cloudURL := currentCloudURL()
+	if cloudURL == "" {
+		cloudURL = DefaultCloudURL
+	}
+
+	installCmd := fmt.Sprintf(
+		`curl -fsSL https://vstats.zsoft.cc/install.sh | sudo bash -s -- --cloud-mode --cloud-url "%s" --cloud-token "%s" --port %d`,
+		cloudURL, currentToken(), port,
+	)
+	if ssl && domain != "" {
+		installCmd += fmt.Sprintf(` --ssl --domain "%s"`, domain)
+	}
+	if version != "" {
+		installCmd += fmt.Sprintf(` --version "%s"`, version)
+	}
+	return installCmd
+}
+
+// agentInstallCommand builds the remote agent install command
+func agentInstallCommand(serverName string) string {
+	cloudURL := currentCloudURL()
+	if cloudURL == "" {
+		cloudURL = "https://api.vstats.zsoft.cc"
+	}
+	return fmt.Sprintf(
+		`curl -fsSL https://vstats.zsoft.cc/agent.sh | sudo bash -s -- --server "%s" --token "%s" --name "%s"`,
+		cloudURL, currentToken(), serverName,
+	)
+}
+
+// agentUninstallCommand is the remote command that removes the agent
+const agentUninstallCommand = `curl -fsSL https://vstats.zsoft.cc/agent.sh | sudo bash -s -- --uninstall`
+
+// sshUninstallAgentCmd removes the agent from a host via SSH
+var sshUninstallAgentCmd = &cobra.Command{
+	Use:   "uninstall-agent <host>",
+	Short: "Uninstall the vStats agent via SSH",
+	Long: `Uninstall the vStats agent from a remote server via SSH.
+
+With --remove-server, the matching server is also deleted from vStats
+Cloud after the agent is removed. The server is found by --server (name or
+ID), defaulting to the host name as 'ssh agent' names it. Deleting asks
+for confirmation unless --force is given.
+
+Examples:
+  vstats ssh uninstall-agent root@192.168.1.1
+  vstats ssh uninstall-agent web-01 --remove-server
+  vstats ssh uninstall-agent web-01.example.com --remove-server --server web-01 --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateSSHFlags(); err != nil {
+			return err
+		}
+		hostArg := args[0]
+		user, host := resolveSSHTarget(hostArg)
+		removeServer, _ := cmd.Flags().GetBool("remove-server")
+
+		var client *Client
+		var server *Server
+		if removeServer {
+			if err := requireLogin(); err != nil {
+				return err
+			}
+			nameOrID, _ := cmd.Flags().GetString("server")
+			if nameOrID == "" {
+				nameOrID = host
+			}
+			client = NewClient()
+			var err error
+			if server, err = findServerByNameOrID(client, nameOrID); err != nil {
+				return err
+			}
+		}
+
+		sshArgs := buildSSHArgs(user, host)
+		if dryRun {
+			fmt.Printf("(dry-run) would uninstall the agent from %s\n", hostArg)
+			fmt.Printf("  remote command: %s\n", agentUninstallCommand)
+			fmt.Printf("  ssh argv: %s\n", formatSSHInvocation(sshArgs, agentUninstallCommand))
+			if server != nil {
+				printDryRun("DELETE", "/api/servers/"+server.ID, nil, "would delete server '%s'", server.Name)
+			}
+			return nil
+		}
+
+		if !quiet {
+			fmt.Printf("Connecting to %s...\n", hostArg)
+			fmt.Println("Uninstalling vStats agent...")
+			fmt.Println()
+		}
+		if err := runSSHCommand(sshArgs, agentUninstallCommand); err != nil {
+			return fmt.Errorf("uninstall failed: %w", err)
+		}
+		if !quiet {
+			fmt.Println()
+			fmt.Printf("✓ Agent uninstalled from %s\n", hostArg)
+		}
+
+		if server == nil {
+			return nil
+		}
+		force, _ := cmd.Flags().GetBool("force")
+		if !force {
+			ok, err := confirm(fmt.Sprintf("Delete server '%s' from vStats Cloud?", server.Name))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Server kept.")
+				return nil
+			}
+		}
+		if err := client.DeleteServer(server.ID); err != nil {
+			return fmt.Errorf("failed to delete server: %w", err)
+		}
+		invalidateServerCache()
+		fmt.Printf("✓ Server '%s' deleted\n", server.Name)
+		return nil
+	},
+}
+
+// printAgentDryRun shows what deploying the agent to a host would do: the
+// server it would create (unless reusing one) and the exact ssh invocation.
+// batch matches the batch-mode ssh used for multi-host deployments.
+func printAgentDryRun(hostArg, serverName, existingServerID string, batch bool) {
+	user, host := resolveSSHTarget(hostArg)
+	if serverName == "" {
+		serverName = host
+	}
+
+	if existingServerID != "" {
+		fmt.Printf("(dry-run) would install the agent for existing server '%s' on %s\n", existingServerID, hostArg)
+	} else {
+		printDryRun("POST", "/api/servers", map[string]string{"name": serverName},
+			"would create server '%s' and install the agent on %s", serverName, hostArg)
+	}
+	sshArgs := buildSSHArgs(user, host)
+	if batch {
+		sshArgs = append([]string{"-o", "BatchMode=yes"}, sshArgs...)
+	}
+	installCmd := agentInstallCommand(serverName)
+	fmt.Printf("  remote command: %s\n", redactToken(installCmd))
+	fmt.Printf("  ssh argv: %s\n", redactToken(formatSSHInvocation(sshArgs, installCmd)))
+}
+
+// formatSSHInvocation renders the ssh command line runSSHCommand would
+// execute, quoting each argument for a POSIX shell
+func formatSSHInvocation(sshArgs []string, command string) string {
+	parts := []string{"ssh"}
+	for _, arg := range append(append([]string{}, sshArgs...), command) {
+		parts = append(parts, shellQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+// shellQuote single-quotes s unless it is made only of safe characters
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("@%+=:,./_-", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// redactToken masks the API token wherever it appears in s
+func redactToken(s string) string {
+	token := currentToken()
+	if token == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, token, redactedValue)
+}
+
 // buildSSHArgs builds SSH command arguments
 func buildSSHArgs(user, host string) []string {
 	args := []string{}
@@ -302,6 +617,14 @@ func buildSSHArgs(user, host string) []string {
 		args = append(args, "-i", sshKey)
 	}
 
+	// Route through a bastion and pass through extra options
+	if sshJump != "" {
+		args = append(args, "-J", sshJump)
+	}
+	for _, opt := range sshOptions {
+		args = append(args, "-o", opt)
+	}
+
 	// Add target
 	target := host
 	if user != "" {
@@ -312,8 +635,14 @@ func buildSSHArgs(user, host string) []string {
 	return args
 }
 
-// runSSHCommand executes a command via SSH using the system ssh client
+// runSSHCommand executes a command via SSH using the system ssh client,
+// attached to the terminal
 func runSSHCommand(sshArgs []string, command string) error {
+	return runSSHCommandIO(sshArgs, command, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// runSSHCommandIO executes a command via SSH with the given streams
+func runSSHCommandIO(sshArgs []string, command string, stdin io.Reader, stdout, stderr io.Writer) error {
 	// Check for ssh
 	sshPath, err := exec.LookPath("ssh")
 	if err != nil {
@@ -323,33 +652,81 @@ func runSSHCommand(sshArgs []string, command string) error {
 	// Build full args: ssh [args] command
 	fullArgs := append(sshArgs, command)
 
-	cmd := exec.Command(sshPath, fullArgs...)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd := exec.CommandContext(commandContext(), sshPath, fullArgs...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 
-	return cmd.Run()
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return &SSHError{ExitCode: exitErr.ExitCode()}
+		}
+		return err
+	}
+	return nil
+}
+
+// sshConnectionFailedCode is the exit code ssh uses for its own errors,
+// as opposed to the exit code of the remote command
+const sshConnectionFailedCode = 255
+
+// SSHError represents a non-zero exit from the ssh client
+type SSHError struct {
+	ExitCode int
+}
+
+// ConnectionFailed reports whether ssh itself failed to connect or authenticate
+func (e *SSHError) ConnectionFailed() bool {
+	return e.ExitCode == sshConnectionFailedCode
+}
+
+func (e *SSHError) Error() string {
+	if e.ConnectionFailed() {
+		return "SSH connection failed - check the host, user, and credentials"
+	}
+	return fmt.Sprintf("remote command failed with exit code %d - see output above", e.ExitCode)
 }
 
 func init() {
 	// Add subcommands
 	sshCmd.AddCommand(sshAgentCmd)
 	sshCmd.AddCommand(sshWebCmd)
+	sshCmd.AddCommand(sshUninstallAgentCmd)
 
 	// Agent deploy flags
 	sshAgentCmd.Flags().StringVarP(&sshUser, "user", "u", "", "SSH username (default: root)")
 	sshAgentCmd.Flags().IntVarP(&sshPort, "port", "p", 0, "SSH port (uses ssh config default)")
 	sshAgentCmd.Flags().StringVarP(&sshKey, "key", "i", "", "SSH private key path")
+	sshAgentCmd.Flags().StringVarP(&sshJump, "jump", "J", "", "Connect through a jump host (user@bastion[:port])")
+	sshAgentCmd.Flags().StringArrayVar(&sshOptions, "ssh-option", nil, "Extra ssh -o option as Key=Value (repeatable)")
 	sshAgentCmd.Flags().String("name", "", "Server name in vStats")
 	sshAgentCmd.Flags().String("server", "", "Use existing server ID instead of creating new")
+	sshAgentCmd.Flags().String("hosts-file", "", "File with one host per line to deploy to")
+	sshAgentCmd.Flags().Int("parallel", 4, "Maximum concurrent deployments for multiple hosts")
+	sshAgentCmd.Flags().Bool("wait", false, "Wait until the agent connects")
+	sshAgentCmd.Flags().Duration("timeout", 120*time.Second, "Give up --wait after this long (0 waits forever)")
+
+	// Agent uninstall flags
+	sshUninstallAgentCmd.Flags().StringVarP(&sshUser, "user", "u", "", "SSH username (default: root)")
+	sshUninstallAgentCmd.Flags().IntVarP(&sshPort, "port", "p", 0, "SSH port (uses ssh config default)")
+	sshUninstallAgentCmd.Flags().StringVarP(&sshKey, "key", "i", "", "SSH private key path")
+	sshUninstallAgentCmd.Flags().StringVarP(&sshJump, "jump", "J", "", "Connect through a jump host (user@bastion[:port])")
+	sshUninstallAgentCmd.Flags().StringArrayVar(&sshOptions, "ssh-option", nil, "Extra ssh -o option as Key=Value (repeatable)")
+	sshUninstallAgentCmd.Flags().Bool("remove-server", false, "Also delete the server from vStats Cloud")
+	sshUninstallAgentCmd.Flags().String("server", "", "Server name or ID to delete (default: the host name)")
+	sshUninstallAgentCmd.Flags().BoolP("force", "f", false, "Delete the server without confirmation (same as --yes)")
 
 	// Web deploy flags
 	sshWebCmd.Flags().StringVarP(&sshUser, "user", "u", "", "SSH username (default: root)")
 	sshWebCmd.Flags().IntVarP(&sshPort, "port", "p", 0, "SSH port (uses ssh config default)")
 	sshWebCmd.Flags().StringVarP(&sshKey, "key", "i", "", "SSH private key path")
+	sshWebCmd.Flags().StringVarP(&sshJump, "jump", "J", "", "Connect through a jump host (user@bastion[:port])")
+	sshWebCmd.Flags().StringArrayVar(&sshOptions, "ssh-option", nil, "Extra ssh -o option as Key=Value (repeatable)")
 	sshWebCmd.Flags().String("name", "", "Web dashboard name")
 	sshWebCmd.Flags().Int("web-port", 3001, "Web dashboard port")
 	sshWebCmd.Flags().String("domain", "", "Custom domain for the dashboard")
 	sshWebCmd.Flags().Bool("ssl", false, "Enable SSL (requires domain)")
+	sshWebCmd.Flags().String("version", "", "Dashboard release to install, e.g. 1.4.2 (default: latest)")
 }
 
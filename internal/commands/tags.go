@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// serverTag is one row of the server tags table
+type serverTag struct {
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// formatTags renders tags as sorted key=value pairs
+func formatTags(tags map[string]string) string {
+	if len(tags) == 0 {
+		return "-"
+	}
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// parseTagFlags parses repeated key=value flag values
+func parseTagFlags(pairs []string) (map[string]string, error) {
+	tags := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid tag %q (expected key=value)", pair)
+		}
+		tags[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return tags, nil
+}
+
+// serverTagsCmd lists and edits a server's tags
+var serverTagsCmd = &cobra.Command{
+	Use:   "tags <id>",
+	Short: "List, add, or remove server tags",
+	Long: `List a server's tags, or change them with --add and --remove.
+
+Tags are key=value labels such as env=prod, used to group servers and to
+filter 'vstats server list --tag'. Adding a key that already exists
+replaces its value. Removals are applied before additions.
+
+Examples:
+  vstats server tags web-01
+  vstats server tags web-01 --add env=prod --add team=web
+  vstats server tags web-01 --remove team
+  vstats server list --tag env=prod`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireLogin(); err != nil {
+			return err
+		}
+
+		addPairs, _ := cmd.Flags().GetStringArray("add")
+		remove, _ := cmd.Flags().GetStringArray("remove")
+		add, err := parseTagFlags(addPairs)
+		if err != nil {
+			return err
+		}
+
+		client := NewClient()
+		server, err := findServerByNameOrID(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		if dryRun && (len(remove) > 0 || len(add) > 0) {
+			if len(remove) > 0 {
+				printDryRun("DELETE", "/api/servers/"+server.ID+"/tags", map[string][]string{"keys": remove},
+					"would remove %d tag(s) from server '%s'", len(remove), server.Name)
+			}
+			if len(add) > 0 {
+				printDryRun("POST", "/api/servers/"+server.ID+"/tags", ServerTags{Tags: add},
+					"would set %d tag(s) on server '%s'", len(add), server.Name)
+			}
+			return nil
+		}
+
+		tags := server.Tags
+		if len(remove) > 0 {
+			if tags, err = client.RemoveServerTags(server.ID, remove); err != nil {
+				return fmt.Errorf("failed to remove tags: %w", err)
+			}
+		}
+		if len(add) > 0 {
+			if tags, err = client.AddServerTags(server.ID, add); err != nil {
+				return fmt.Errorf("failed to add tags: %w", err)
+			}
+		}
+		if len(remove) > 0 || len(add) > 0 {
+			invalidateServerCache()
+		}
+
+		rows := make([]serverTag, 0, len(tags))
+		for key, value := range tags {
+			rows = append(rows, serverTag{Key: key, Value: value})
+		}
+		sort.Slice(rows, func(i, j int) bool { return rows[i].Key < rows[j].Key })
+
+		switch outputFmt {
+		case "json":
+			return OutputJSON(rows)
+		case "jsonl":
+			return OutputJSONL(rows)
+		case "yaml":
+			return OutputYAML(rows)
+		case "csv":
+			return OutputCSV(rows)
+		case "template":
+			return OutputTemplate(rows)
+		default:
+			if len(rows) == 0 {
+				fmt.Printf("Server '%s' has no tags.\n", server.Name)
+				return nil
+			}
+			table := NewTable("KEY", "VALUE")
+			for _, r := range rows {
+				table.AddRow(r.Key, r.Value)
+			}
+			table.Render()
+		}
+		return nil
+	},
+}
+
+func init() {
+	serverCmd.AddCommand(serverTagsCmd)
+
+	serverTagsCmd.Flags().StringArray("add", nil, "add or replace a tag, e.g. env=prod (repeatable)")
+	serverTagsCmd.Flags().StringArray("remove", nil, "remove the tag with this key (repeatable)")
+}
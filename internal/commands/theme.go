@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ThemeConfig selects a built-in color theme and optional per-role overrides
+type ThemeConfig struct {
+	Name   string            `yaml:"name,omitempty" json:"name,omitempty"`
+	Colors map[string]string `yaml:"colors,omitempty" json:"colors,omitempty"`
+}
+
+// Theme color roles
+const (
+	RoleHeader   = "header"
+	RoleOnline   = "online"
+	RoleOffline  = "offline"
+	RolePending  = "pending"
+	RoleUnknown  = "unknown"
+	RoleWarning  = "warning"
+	RoleCritical = "critical"
+)
+
+// themeRoles lists every role a theme can color
+var themeRoles = []string{RoleHeader, RoleOnline, RoleOffline, RolePending, RoleUnknown, RoleWarning, RoleCritical}
+
+// builtinThemes are the themes selectable by name
+var builtinThemes = map[string]map[string]string{
+	"dark": {
+		RoleHeader:   "cyan",
+		RoleOnline:   "green",
+		RoleOffline:  "red",
+		RolePending:  "yellow",
+		RoleUnknown:  "gray",
+		RoleWarning:  "yellow",
+		RoleCritical: "red",
+	},
+	"light": {
+		RoleHeader:   "blue",
+		RoleOnline:   "28",
+		RoleOffline:  "160",
+		RolePending:  "130",
+		RoleUnknown:  "242",
+		RoleWarning:  "130",
+		RoleCritical: "160",
+	},
+	"colorblind": {
+		RoleHeader:   "cyan",
+		RoleOnline:   "33",
+		RoleOffline:  "208",
+		RolePending:  "228",
+		RoleUnknown:  "gray",
+		RoleWarning:  "228",
+		RoleCritical: "208",
+	},
+}
+
+// defaultThemeName is used when no theme is configured
+const defaultThemeName = "dark"
+
+// namedColors maps color names to ANSI escape codes
+var namedColors = map[string]string{
+	"black":   "\033[30m",
+	"red":     ColorRed,
+	"green":   ColorGreen,
+	"yellow":  ColorYellow,
+	"blue":    ColorBlue,
+	"magenta": "\033[35m",
+	"cyan":    ColorCyan,
+	"white":   "\033[37m",
+	"gray":    ColorGray,
+}
+
+// themeColor returns the ANSI escape code for a role in the active theme
+func themeColor(role string) string {
+	if spec, ok := cfg.Theme.Colors[role]; ok {
+		if code, err := parseColorSpec(spec); err == nil {
+			return code
+		}
+	}
+
+	theme, ok := builtinThemes[cfg.Theme.Name]
+	if !ok {
+		theme = builtinThemes[defaultThemeName]
+	}
+	code, _ := parseColorSpec(theme[role])
+	return code
+}
+
+// parseColorSpec converts a color name or 256-color code to an escape code
+func parseColorSpec(spec string) (string, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	if code, ok := namedColors[spec]; ok {
+		return code, nil
+	}
+	if n, err := strconv.Atoi(spec); err == nil && n >= 0 && n <= 255 {
+		return fmt.Sprintf("\033[38;5;%dm", n), nil
+	}
+	return "", fmt.Errorf("invalid color %q (use a name like red or a 256-color code 0-255)", spec)
+}
+
+// isThemeRole reports whether role is a known theme color role
+func isThemeRole(role string) bool {
+	for _, r := range themeRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// themeNames returns the built-in theme names in sorted order
+func themeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// setThemeValue applies a theme or theme.<role> config key
+func setThemeValue(key, value string) error {
+	if key == "theme" {
+		if _, ok := builtinThemes[value]; !ok {
+			return fmt.Errorf("unknown theme: %s (available: %s)", value, strings.Join(themeNames(), ", "))
+		}
+		cfg.Theme.Name = value
+		return nil
+	}
+
+	role := strings.TrimPrefix(key, "theme.")
+	if !isThemeRole(role) {
+		return fmt.Errorf("unknown theme role: %s (available: %s)", role, strings.Join(themeRoles, ", "))
+	}
+	if _, err := parseColorSpec(value); err != nil {
+		return err
+	}
+	if cfg.Theme.Colors == nil {
+		cfg.Theme.Colors = make(map[string]string)
+	}
+	cfg.Theme.Colors[role] = value
+	return nil
+}
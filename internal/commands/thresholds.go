@@ -0,0 +1,200 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ThresholdLevels are the warning and critical levels for a metric, in percent
+type ThresholdLevels struct {
+	Warning  float64 `yaml:"warning,omitempty" json:"warning,omitempty"`
+	Critical float64 `yaml:"critical,omitempty" json:"critical,omitempty"`
+}
+
+// ThresholdConfig holds per-metric alert thresholds
+type ThresholdConfig struct {
+	CPU    ThresholdLevels `yaml:"cpu,omitempty" json:"cpu,omitempty"`
+	Memory ThresholdLevels `yaml:"memory,omitempty" json:"memory,omitempty"`
+	Disk   ThresholdLevels `yaml:"disk,omitempty" json:"disk,omitempty"`
+	GPU    ThresholdLevels `yaml:"gpu,omitempty" json:"gpu,omitempty"`
+}
+
+// Default threshold levels used when none are configured
+const (
+	DefaultWarningThreshold  = 75.0
+	DefaultCriticalThreshold = 90.0
+)
+
+// Threshold states
+const (
+	StateOK       = "ok"
+	StateWarning  = "warning"
+	StateCritical = "critical"
+)
+
+// Threshold metric names
+const (
+	MetricCPU    = "cpu"
+	MetricMemory = "memory"
+	MetricDisk   = "disk"
+	MetricGPU    = "gpu"
+)
+
+// thresholdMetrics lists the metrics that support thresholds
+var thresholdMetrics = []string{MetricCPU, MetricMemory, MetricDisk, MetricGPU}
+
+// thresholdLevels returns a pointer to the configured levels for a metric
+func thresholdLevels(metric string) *ThresholdLevels {
+	switch metric {
+	case MetricCPU:
+		return &cfg.Thresholds.CPU
+	case MetricMemory:
+		return &cfg.Thresholds.Memory
+	case MetricDisk:
+		return &cfg.Thresholds.Disk
+	case MetricGPU:
+		return &cfg.Thresholds.GPU
+	default:
+		return nil
+	}
+}
+
+// thresholdsFor returns the effective levels for a metric, filling in defaults
+func thresholdsFor(metric string) ThresholdLevels {
+	levels := ThresholdLevels{Warning: DefaultWarningThreshold, Critical: DefaultCriticalThreshold}
+	if configured := thresholdLevels(metric); configured != nil {
+		if configured.Warning > 0 {
+			levels.Warning = configured.Warning
+		}
+		if configured.Critical > 0 {
+			levels.Critical = configured.Critical
+		}
+	}
+	return levels
+}
+
+// thresholdState evaluates a percentage against a metric's thresholds
+func thresholdState(metric string, value float64) string {
+	levels := thresholdsFor(metric)
+	switch {
+	case value >= levels.Critical:
+		return StateCritical
+	case value >= levels.Warning:
+		return StateWarning
+	default:
+		return StateOK
+	}
+}
+
+// thresholdColor returns the theme color for a threshold state
+func thresholdColor(state string) string {
+	switch state {
+	case StateCritical:
+		return themeColor(RoleCritical)
+	case StateWarning:
+		return themeColor(RoleWarning)
+	default:
+		return themeColor(RoleOnline)
+	}
+}
+
+// formatThresholdPercent formats a percentage colored by its threshold state
+func formatThresholdPercent(metric string, value float64) string {
+	return color(thresholdColor(thresholdState(metric, value)), formatPercent(value))
+}
+
+// setThresholdValue applies a thresholds.<metric>.<level> config key
+func setThresholdValue(key, value string) error {
+	parts := strings.Split(key, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("invalid threshold key: %s (use thresholds.<metric>.<warning|critical>)", key)
+	}
+
+	levels := thresholdLevels(parts[1])
+	if levels == nil {
+		return fmt.Errorf("unknown threshold metric: %s (available: %s)", parts[1], strings.Join(thresholdMetrics, ", "))
+	}
+
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil || percent <= 0 || percent > 100 {
+		return fmt.Errorf("invalid threshold: %s (must be a percentage between 0 and 100)", value)
+	}
+
+	previous := *levels
+	switch parts[2] {
+	case "warning":
+		levels.Warning = percent
+	case "critical":
+		levels.Critical = percent
+	default:
+		return fmt.Errorf("unknown threshold level: %s (use warning or critical)", parts[2])
+	}
+	if err := checkThresholdOrder(parts[1]); err != nil {
+		*levels = previous
+		return err
+	}
+	return nil
+}
+
+// checkThresholdOrder checks that a metric's effective warning level is
+// below its critical level
+func checkThresholdOrder(metric string) error {
+	levels := thresholdsFor(metric)
+	if levels.Warning >= levels.Critical {
+		return fmt.Errorf("%s warning threshold (%s) must be below the critical threshold (%s)",
+			metric, formatPercent(levels.Warning), formatPercent(levels.Critical))
+	}
+	return nil
+}
+
+// AnnotatedValue is a metric value together with its threshold state
+type AnnotatedValue struct {
+	Value float64 `json:"value"`
+	State string  `json:"state"`
+}
+
+// AnnotatedMetrics is ServerMetrics with threshold states attached to the
+// percentage-based metrics. GPUUsage is the busiest GPU's utilization.
+type AnnotatedMetrics struct {
+	*ServerMetrics
+	CPUUsage    *AnnotatedValue `json:"cpu_usage,omitempty"`
+	MemoryUsage *AnnotatedValue `json:"memory_usage,omitempty"`
+	DiskUsage   *AnnotatedValue `json:"disk_usage,omitempty"`
+	GPUUsage    *AnnotatedValue `json:"gpu_usage,omitempty"`
+}
+
+// annotateMetrics evaluates thresholds for a set of metrics
+func annotateMetrics(m *ServerMetrics) *AnnotatedMetrics {
+	if m == nil {
+		return nil
+	}
+
+	annotated := &AnnotatedMetrics{ServerMetrics: m}
+	if m.CPUUsage != nil {
+		annotated.CPUUsage = annotateValue(MetricCPU, *m.CPUUsage)
+	}
+	if pct, ok := usagePercent(m.MemoryUsed, m.MemoryTotal); ok {
+		annotated.MemoryUsage = annotateValue(MetricMemory, pct)
+	}
+	if pct, ok := usagePercent(m.DiskUsed, m.DiskTotal); ok {
+		annotated.DiskUsage = annotateValue(MetricDisk, pct)
+	}
+	if pct, ok := m.maxGPUUtilization(); ok {
+		annotated.GPUUsage = annotateValue(MetricGPU, pct)
+	}
+	return annotated
+}
+
+// annotateValue wraps a percentage with its threshold state
+func annotateValue(metric string, value float64) *AnnotatedValue {
+	return &AnnotatedValue{Value: value, State: thresholdState(metric, value)}
+}
+
+// usagePercent computes used/total as a percentage
+func usagePercent(used, total *int64) (float64, bool) {
+	if used == nil || total == nil || *total <= 0 {
+		return 0, false
+	}
+	return float64(*used) / float64(*total) * 100, true
+}
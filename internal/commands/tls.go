@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+var (
+	insecureTLS    bool
+	insecureTLSSet bool
+	caCertFile     string
+
+	// clientTLSConfig is applied to API clients; nil uses the defaults
+	clientTLSConfig *tls.Config
+)
+
+// loadTLSConfig builds the API client TLS settings from --insecure and
+// --ca-cert, falling back to the insecure and ca_cert config keys
+func loadTLSConfig() error {
+	insecure := cfg.Insecure
+	if insecureTLSSet {
+		insecure = insecureTLS
+	}
+	caFile := caCertFile
+	if caFile == "" {
+		caFile = cfg.CACert
+	}
+
+	clientTLSConfig = nil
+	if !insecure && caFile == "" {
+		return nil
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecure}
+	if caFile != "" {
+		pool, err := loadCACertPool(caFile)
+		if err != nil {
+			return err
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if insecure {
+		// Shown even with --quiet so it isn't left on by accident
+		fmt.Fprintln(os.Stderr, color(themeColor(RoleCritical),
+			"WARNING: TLS certificate verification is disabled (--insecure); connections can be intercepted"))
+	}
+	clientTLSConfig = tlsConfig
+	return nil
+}
+
+// loadCACertPool returns the system roots plus the certificates in a PEM file
+func loadCACertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// parseBoolValue parses a boolean config value
+func parseBoolValue(key, value string) (bool, error) {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %s (use true or false)", key, value)
+	}
+	return b, nil
+}
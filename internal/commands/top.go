@@ -0,0 +1,241 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// Terminal control sequences used by full-screen views
+const (
+	termAltScreenOn  = "\033[?1049h"
+	termAltScreenOff = "\033[?1049l"
+	termHideCursor   = "\033[?25l"
+	termShowCursor   = "\033[?25h"
+	termClearScreen  = "\033[H\033[2J"
+)
+
+// topSortKeys maps keypresses to sort keys in the top view
+var topSortKeys = map[byte]string{
+	'c': "cpu",
+	'd': "disk",
+	'm': "mem",
+	'n': "name",
+	's': "status",
+}
+
+// serverTopCmd shows a live, sorted overview of all servers
+var serverTopCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Live overview of all servers",
+	Long: `Show a full-screen, auto-refreshing overview of all servers.
+
+Metrics over their warning/critical thresholds are highlighted, and totals
+for the fleet are shown at the bottom.
+
+Keys:
+  c   sort by CPU
+  m   sort by memory
+  d   sort by disk
+  n   sort by name
+  s   sort by status
+  r   reverse the sort order
+  q   quit (Ctrl-C also works)
+
+Examples:
+  vstats server top
+  vstats server top --sort mem --interval 5s`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireLogin(); err != nil {
+			return err
+		}
+
+		interval, _ := cmd.Flags().GetDuration("interval")
+		sortKey, _ := cmd.Flags().GetString("sort")
+		if interval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+		if err := validateSortKey(sortKey); err != nil {
+			return err
+		}
+		client := NewClient()
+
+		view := &topView{}
+		view.setSort(sortKey)
+
+		// Read single keypresses in raw mode when attached to a terminal
+		keys := make(chan byte)
+		stdinFd := int(os.Stdin.Fd())
+		if term.IsTerminal(stdinFd) {
+			oldState, err := term.MakeRaw(stdinFd)
+			if err != nil {
+				return fmt.Errorf("failed to set terminal raw mode: %w", err)
+			}
+			defer term.Restore(stdinFd, oldState)
+			go readKeys(keys)
+		}
+
+		fmt.Print(termAltScreenOn + termHideCursor)
+		defer fmt.Print(termShowCursor + termAltScreenOff)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		view.refresh(client)
+		for {
+			view.render()
+
+			select {
+			case <-cmd.Context().Done():
+				return nil
+			case <-ticker.C:
+				view.refresh(client)
+			case key := <-keys:
+				switch key {
+				case 'q', 3: // 3 is Ctrl-C in raw mode
+					return nil
+				case 'r':
+					view.descending = !view.descending
+				default:
+					if sortKey, ok := topSortKeys[key]; ok {
+						view.setSort(sortKey)
+					}
+				}
+			}
+		}
+	},
+}
+
+// topView holds the state of the top screen between refreshes
+type topView struct {
+	servers    []Server
+	err        error
+	updated    time.Time
+	sortKey    string
+	descending bool
+}
+
+// refresh fetches the latest server list
+func (v *topView) refresh(client *Client) {
+	servers, err := client.ListServers()
+	v.err = err
+	if err == nil {
+		v.servers = servers
+		v.updated = time.Now()
+	}
+}
+
+// setSort switches the sort column, using the natural direction for it
+func (v *topView) setSort(key string) {
+	v.sortKey = key
+	v.descending = key == "cpu" || key == "mem" || key == "disk"
+}
+
+// render draws the screen
+func (v *topView) render() {
+	sortServers(v.servers, v.sortKey, v.descending)
+
+	var buf bytes.Buffer
+	direction := "asc"
+	if v.descending {
+		direction = "desc"
+	}
+	fmt.Fprintf(&buf, "vStats top - %d servers - sort: %s (%s) - updated %s\n\n",
+		len(v.servers), v.sortKey, direction, v.updated.Format("15:04:05"))
+
+	table := NewTable("NAME", "STATUS", "CPU", "MEM", "DISK", "LAST SEEN")
+	table.Writer = &buf
+	online := 0
+	cpuTotal, cpuCount := 0.0, 0
+	for _, s := range v.servers {
+		name := s.Name
+		if topServerCritical(s) {
+			name = color(themeColor(RoleCritical), name)
+		}
+		table.AddRow(
+			name,
+			formatStatus(s.Status),
+			topMetricCell(s, "cpu"),
+			topMetricCell(s, "mem"),
+			topMetricCell(s, "disk"),
+			formatTimestamp(s.LastSeenAt),
+		)
+
+		if rank, ok := statusRank[strings.ToLower(s.Status)]; ok && rank == 0 {
+			online++
+		}
+		if cpu, ok := conditionMetricValue("cpu", s.Metrics); ok {
+			cpuTotal += cpu
+			cpuCount++
+		}
+	}
+	table.Render()
+
+	avgCPU := "-"
+	if cpuCount > 0 {
+		avgCPU = formatPercent(cpuTotal / float64(cpuCount))
+	}
+	fmt.Fprintf(&buf, "\nTotal: %d servers   Online: %d   Avg CPU: %s\n", len(v.servers), online, avgCPU)
+
+	if v.err != nil {
+		fmt.Fprintf(&buf, "\n%s\n", color(themeColor(RoleCritical), "Refresh failed: "+v.err.Error()))
+	}
+	fmt.Fprintf(&buf, "\n%s\n", color(ColorGray, "c:cpu  m:memory  d:disk  n:name  s:status  r:reverse  q:quit"))
+
+	// Raw mode disables newline translation, so emit explicit carriage returns
+	fmt.Print(termClearScreen + strings.ReplaceAll(buf.String(), "\n", "\r\n"))
+}
+
+// topThresholdMetrics maps top's metric names to threshold metric names
+var topThresholdMetrics = map[string]string{
+	"cpu":  MetricCPU,
+	"mem":  MetricMemory,
+	"disk": MetricDisk,
+}
+
+// topMetricCell formats a percentage metric for the top table, colored by
+// its threshold state
+func topMetricCell(s Server, metric string) string {
+	value, ok := conditionMetricValue(metric, s.Metrics)
+	if !ok {
+		return "-"
+	}
+	return formatThresholdPercent(topThresholdMetrics[metric], value)
+}
+
+// topServerCritical reports whether any of a server's metrics is over its
+// critical threshold
+func topServerCritical(s Server) bool {
+	for metric, thresholdMetric := range topThresholdMetrics {
+		if value, ok := conditionMetricValue(metric, s.Metrics); ok && thresholdState(thresholdMetric, value) == StateCritical {
+			return true
+		}
+	}
+	return false
+}
+
+// readKeys forwards single bytes read from stdin until it is closed
+func readKeys(keys chan<- byte) {
+	buf := make([]byte, 1)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return
+		}
+		if n == 1 {
+			keys <- buf[0]
+		}
+	}
+}
+
+func init() {
+	serverCmd.AddCommand(serverTopCmd)
+
+	serverTopCmd.Flags().Duration("interval", 3*time.Second, "refresh interval")
+	serverTopCmd.Flags().String("sort", "cpu", "initial sort: cpu, mem, disk, name, status")
+}
@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// updateCheckInterval is how long a latest-release lookup is cached
+const updateCheckInterval = 24 * time.Hour
+
+// updateCheckTimeout bounds the latest-release request
+const updateCheckTimeout = 5 * time.Second
+
+// CLIRelease describes the latest published CLI release
+type CLIRelease struct {
+	Version      string `json:"version" yaml:"version"`
+	ChangelogURL string `json:"changelog_url,omitempty" yaml:"changelog_url,omitempty"`
+}
+
+// updateCheckCache is the cached result of the last latest-release lookup
+type updateCheckCache struct {
+	CloudURL  string     `json:"cloud_url"`
+	CheckedAt time.Time  `json:"checked_at"`
+	Latest    CLIRelease `json:"latest"`
+}
+
+// VersionCheck is the result of version --check
+type VersionCheck struct {
+	Current         string `json:"current" yaml:"current"`
+	Latest          string `json:"latest" yaml:"latest"`
+	UpdateAvailable bool   `json:"update_available" yaml:"update_available"`
+	ChangelogURL    string `json:"changelog_url,omitempty" yaml:"changelog_url,omitempty"`
+}
+
+// GetLatestCLIRelease returns the latest published CLI release
+func (c *Client) GetLatestCLIRelease() (*CLIRelease, error) {
+	var resp CLIRelease
+	if err := c.Do("GET", "/api/cli/latest", nil, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Version == "" {
+		return nil, fmt.Errorf("release endpoint returned no version")
+	}
+	return &resp, nil
+}
+
+// getUpdateCheckPath returns the latest-release cache file path
+func getUpdateCheckPath() (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache", "latest-version.json"), nil
+}
+
+// latestRelease returns the latest CLI release, from the cache when it was
+// looked up within updateCheckInterval
+func latestRelease() (*CLIRelease, error) {
+	path, err := getUpdateCheckPath()
+	if err != nil {
+		return nil, err
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		var cache updateCheckCache
+		if json.Unmarshal(data, &cache) == nil && cache.CloudURL == currentCloudURL() &&
+			time.Since(cache.CheckedAt) < updateCheckInterval {
+			return &cache.Latest, nil
+		}
+	}
+
+	client := newClientFor(currentCloudURL(), "")
+	client.HTTPClient.Timeout = updateCheckTimeout
+	release, err := client.GetLatestCLIRelease()
+	if err != nil {
+		return nil, err
+	}
+
+	// A failed cache write only means the next check hits the network again
+	if data, err := json.Marshal(updateCheckCache{CloudURL: currentCloudURL(), CheckedAt: time.Now(), Latest: *release}); err == nil {
+		if os.MkdirAll(filepath.Dir(path), 0700) == nil {
+			_ = os.WriteFile(path, data, 0600)
+		}
+	}
+	return release, nil
+}
+
+// checkVersion compares the running version with the latest release.
+// Development builds are never reported as outdated.
+func checkVersion() (*VersionCheck, error) {
+	release, err := latestRelease()
+	if err != nil {
+		return nil, err
+	}
+	check := &VersionCheck{
+		Current:      version,
+		Latest:       strings.TrimPrefix(release.Version, "v"),
+		ChangelogURL: release.ChangelogURL,
+	}
+	if releaseVersionPattern.MatchString(version) {
+		check.UpdateAvailable = compareVersions(version, release.Version) < 0
+	}
+	return check, nil
+}
+
+// releaseVersionPattern matches release versions such as 1.4.2, v1.4.2,
+// or 1.5.0-rc.1
+var releaseVersionPattern = regexp.MustCompile(`^v?[0-9]+\.[0-9]+\.[0-9]+(-[0-9A-Za-z.-]+)?$`)
+
+// normalizeReleaseVersion validates a release version and strips any
+// leading "v"
+func normalizeReleaseVersion(v string) (string, error) {
+	if !releaseVersionPattern.MatchString(v) {
+		return "", fmt.Errorf("invalid version: %q (expected a release like 1.4.2)", v)
+	}
+	return strings.TrimPrefix(v, "v"), nil
+}
+
+// compareVersions compares dotted version strings such as "1.6.0" or
+// "v1.10.2-beta", returning -1, 0, or 1. Missing components count as zero
+// and any pre-release or build suffix is ignored.
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for len(pa) < len(pb) {
+		pa = append(pa, 0)
+	}
+	for len(pb) < len(pa) {
+		pb = append(pb, 0)
+	}
+
+	for i := range pa {
+		switch {
+		case pa[i] < pb[i]:
+			return -1
+		case pa[i] > pb[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionParts splits a version string into its numeric components
+func versionParts(v string) []int {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if idx := strings.IndexAny(v, "-+"); idx >= 0 {
+		v = v[:idx]
+	}
+
+	var parts []int
+	for _, p := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// serverWatchCmd keeps a server's metrics on screen, refreshing on an interval
+var serverWatchCmd = &cobra.Command{
+	Use:   "watch <id>",
+	Short: "Watch server metrics live",
+	Long: `Refresh a server's metrics on an interval until interrupted.
+
+The screen is redrawn on every poll. Watching stops when the server goes
+offline, after --count refreshes, or on Ctrl-C.
+
+Examples:
+  vstats server watch web-01
+  vstats server watch web-01 --interval 2s
+  vstats server watch web-01 --count 10`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireLogin(); err != nil {
+			return err
+		}
+
+		interval, _ := cmd.Flags().GetDuration("interval")
+		count, _ := cmd.Flags().GetInt("count")
+		if interval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+		client := NewClient()
+
+		server, err := findServerByNameOrID(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(termHideCursor)
+		defer fmt.Print(termShowCursor)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var metrics *ServerMetrics
+		var lastPoll time.Time
+		for refreshes := 1; ; refreshes++ {
+			latest, pollErr := client.GetServer(server.ID)
+			if pollErr == nil {
+				server = latest
+				var resp *MetricsResponse
+				if resp, pollErr = client.GetServerMetrics(server.ID); pollErr == nil {
+					metrics = resp.Metrics
+					lastPoll = time.Now()
+				}
+			}
+
+			fmt.Print(termClearScreen)
+			if metrics != nil {
+				printMetrics(server, metrics)
+			} else {
+				fmt.Printf("Metrics for %s\n", server.Name)
+				fmt.Println(strings.Repeat("=", 40))
+				fmt.Println()
+				fmt.Println("No metrics available for this server.")
+			}
+			fmt.Println()
+			if lastPoll.IsZero() {
+				fmt.Println("Last updated: never")
+			} else {
+				fmt.Printf("Last updated: %s (every %s, Ctrl-C to stop)\n", lastPoll.Format("15:04:05"), interval)
+			}
+			if pollErr != nil {
+				fmt.Println(color(themeColor(RoleCritical), "Refresh failed: "+pollErr.Error()))
+			}
+
+			if strings.EqualFold(server.Status, "offline") {
+				fmt.Printf("\nServer '%s' went offline, stopping.\n", server.Name)
+				return nil
+			}
+			if count > 0 && refreshes >= count {
+				return nil
+			}
+
+			select {
+			case <-cmd.Context().Done():
+				fmt.Println()
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+func init() {
+	serverCmd.AddCommand(serverWatchCmd)
+
+	serverWatchCmd.Flags().Duration("interval", 5*time.Second, "refresh interval")
+	serverWatchCmd.Flags().Int("count", 0, "stop after this many refreshes (0 = until interrupted)")
+}
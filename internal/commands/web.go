@@ -2,7 +2,6 @@ package commands
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -31,6 +30,12 @@ type UserPlan struct {
 	IsPro        bool   `json:"is_pro" yaml:"is_pro"`
 }
 
+// WebStatus is the combined plan and instance listing shown by web status
+type WebStatus struct {
+	Plan      *UserPlan     `json:"plan" yaml:"plan"`
+	Instances []WebInstance `json:"instances" yaml:"instances"`
+}
+
 // webCmd represents the web command group
 var webCmd = &cobra.Command{
 	Use:   "web",
@@ -71,8 +76,14 @@ var webListCmd = &cobra.Command{
 		switch outputFmt {
 		case "json":
 			return OutputJSON(instances)
+		case "jsonl":
+			return OutputJSONL(instances)
 		case "yaml":
 			return OutputYAML(instances)
+		case "csv":
+			return OutputCSV(instances)
+		case "template":
+			return OutputTemplate(instances)
 		default:
 			if len(instances) == 0 {
 				fmt.Println("No web instances found.")
@@ -80,16 +91,41 @@ var webListCmd = &cobra.Command{
 				return nil
 			}
 
-			table := NewTable("NAME", "HOST", "PORT", "STATUS", "URL", "CREATED")
-			for _, w := range instances {
-				table.AddRow(
+			// Health checks are opt-in since they hit every instance
+			check, _ := cmd.Flags().GetBool("check")
+			var health []*WebInstanceStatus
+			if check {
+				health = make([]*WebInstanceStatus, len(instances))
+				forEachParallel(len(instances), concurrency, func(i int) {
+					if status, err := client.CheckWebInstance(instances[i].ID); err == nil {
+						health[i] = status
+					}
+				})
+			}
+
+			headers := []string{"NAME", "HOST", "PORT", "STATUS", "URL", "CREATED"}
+			if check {
+				headers = append(headers, "HEALTH", "RESPONSE")
+			}
+
+			table := NewTable(headers...)
+			for i, w := range instances {
+				row := []string{
 					w.Name,
 					w.Host,
 					fmt.Sprintf("%d", w.Port),
 					formatWebStatus(w.Status),
 					w.URL,
-					formatTimeAgo(&w.CreatedAt),
-				)
+					formatTimestamp(&w.CreatedAt),
+				}
+				if check {
+					if health[i] != nil {
+						row = append(row, formatWebStatus(health[i].Status), health[i].ResponseTime)
+					} else {
+						row = append(row, color(themeColor(RoleCritical), "✗ check failed"), "-")
+					}
+				}
+				table.AddRow(row...)
 			}
 			table.Render()
 		}
@@ -124,12 +160,18 @@ To uninstall from the server, SSH in and run:
 			return fmt.Errorf("web instance not found: %s", instanceID)
 		}
 
+		if dryRun {
+			printDryRun("DELETE", "/api/web/instances/"+instance.ID, nil, "would remove web instance '%s'", instance.Name)
+			return nil
+		}
+
 		// Confirm removal
 		if !force {
-			fmt.Printf("Are you sure you want to remove web instance '%s'? [y/N] ", instance.Name)
-			var confirm string
-			fmt.Scanln(&confirm)
-			if strings.ToLower(confirm) != "y" && strings.ToLower(confirm) != "yes" {
+			ok, err := confirm(fmt.Sprintf("Are you sure you want to remove web instance '%s'?", instance.Name))
+			if err != nil {
+				return err
+			}
+			if !ok {
 				fmt.Println("Cancelled.")
 				return nil
 			}
@@ -170,17 +212,18 @@ var webStatusCmd = &cobra.Command{
 			return fmt.Errorf("failed to list instances: %w", err)
 		}
 
+		status := WebStatus{Plan: plan, Instances: instances}
 		switch outputFmt {
 		case "json":
-			return OutputJSON(map[string]interface{}{
-				"plan":      plan,
-				"instances": instances,
-			})
+			return OutputJSON(status)
+		case "jsonl":
+			return OutputJSONL(status)
 		case "yaml":
-			return OutputYAML(map[string]interface{}{
-				"plan":      plan,
-				"instances": instances,
-			})
+			return OutputYAML(status)
+		case "csv":
+			return OutputCSV(instances)
+		case "template":
+			return OutputTemplate(instances)
 		default:
 			fmt.Println("Web Dashboard Status")
 			fmt.Println("====================")
@@ -199,7 +242,7 @@ var webStatusCmd = &cobra.Command{
 				fmt.Println("Deploy with: vstats ssh web <host>")
 			}
 
-			if !plan.IsPro {
+			if !plan.IsPro && !quiet {
 				fmt.Println()
 				fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 				fmt.Println("Upgrade to Pro for unlimited web instances!")
@@ -243,8 +286,14 @@ var webCheckCmd = &cobra.Command{
 		switch outputFmt {
 		case "json":
 			return OutputJSON(status)
+		case "jsonl":
+			return OutputJSONL(status)
 		case "yaml":
 			return OutputYAML(status)
+		case "csv":
+			return OutputCSV(status)
+		case "template":
+			return OutputTemplate(status)
 		default:
 			fmt.Printf("Status:       %s\n", formatWebStatus(status.Status))
 			fmt.Printf("URL:          %s\n", instance.URL)
@@ -261,21 +310,8 @@ var webCheckCmd = &cobra.Command{
 // Helper function to format web status
 func formatWebStatus(status string) string {
 	switch status {
-	case "online":
-		if noColor {
-			return "● online"
-		}
-		return "\033[32m● online\033[0m"
-	case "offline":
-		if noColor {
-			return "○ offline"
-		}
-		return "\033[31m○ offline\033[0m"
-	case "pending":
-		if noColor {
-			return "◐ pending"
-		}
-		return "\033[33m◐ pending\033[0m"
+	case "online", "offline", "pending":
+		return formatStatus(status)
 	default:
 		return status
 	}
@@ -374,7 +410,10 @@ func init() {
 	webCmd.AddCommand(webStatusCmd)
 	webCmd.AddCommand(webCheckCmd)
 
+	// List flags
+	webListCmd.Flags().Bool("check", false, "run a live health check on each instance")
+
 	// Remove flags
-	webRemoveCmd.Flags().BoolP("force", "f", false, "Force removal without confirmation")
+	webRemoveCmd.Flags().BoolP("force", "f", false, "Force removal without confirmation (same as --yes)")
 }
 
@@ -0,0 +1,412 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// webLogFollowInterval is how often web logs --follow polls for new lines
+const webLogFollowInterval = 2 * time.Second
+
+// webRestartPollInterval is how often web restart checks the instance
+const webRestartPollInterval = 3 * time.Second
+
+// WebLogLine is one line of a web instance's log
+type WebLogLine struct {
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+	Message   string    `json:"message" yaml:"message"`
+}
+
+// WebLogOptions selects which log lines to fetch. Zero values are left out
+// of the request.
+type WebLogOptions struct {
+	Tail  int
+	Since time.Time
+}
+
+// GetWebInstanceLogs returns log lines of a web instance, oldest first
+func (c *Client) GetWebInstanceLogs(id string, opts WebLogOptions) ([]WebLogLine, error) {
+	query := url.Values{}
+	if opts.Tail > 0 {
+		query.Set("tail", strconv.Itoa(opts.Tail))
+	}
+	if !opts.Since.IsZero() {
+		query.Set("since", opts.Since.UTC().Format(time.RFC3339Nano))
+	}
+	path := "/web/instances/" + id + "/logs"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var lines []WebLogLine
+	err := c.get(path, &lines)
+	return lines, err
+}
+
+// RestartWebInstance asks a web instance to restart
+func (c *Client) RestartWebInstance(id string) error {
+	return c.post("/web/instances/"+id+"/restart", nil, nil)
+}
+
+// webLogsCmd prints the logs of a web instance
+var webLogsCmd = &cobra.Command{
+	Use:   "logs <id>",
+	Short: "Show web instance logs",
+	Long: `Show the most recent log lines of a web dashboard instance.
+
+With --follow, new lines are printed as they arrive until Ctrl-C.
+
+Examples:
+  vstats web logs <id>
+  vstats web logs <id> --tail 500
+  vstats web logs <id> --follow`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireLogin(); err != nil {
+			return err
+		}
+
+		tail, _ := cmd.Flags().GetInt("tail")
+		follow, _ := cmd.Flags().GetBool("follow")
+		client := NewClient()
+
+		instance, err := getWebInstance(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		lines, err := client.GetWebInstanceLogs(instance.ID, WebLogOptions{Tail: tail})
+		if err != nil {
+			return fmt.Errorf("failed to get logs: %w", err)
+		}
+		if !follow {
+			switch outputFmt {
+			case "json":
+				return OutputJSON(lines)
+			case "jsonl":
+				return OutputJSONL(lines)
+			case "yaml":
+				return OutputYAML(lines)
+			case "csv":
+				return OutputCSV(lines)
+			case "template":
+				return OutputTemplate(lines)
+			}
+		}
+		if err := printWebLogLines(lines); err != nil {
+			return err
+		}
+		if !follow {
+			return nil
+		}
+
+		var cursor webLogCursor
+		cursor.advance(lines)
+		err = pollUntil(webLogFollowInterval, 0, func() (bool, error) {
+			lines, err := client.GetWebInstanceLogs(instance.ID, WebLogOptions{Since: cursor.since})
+			if err != nil {
+				return false, fmt.Errorf("failed to get logs: %w", err)
+			}
+			fresh := cursor.unseen(lines)
+			cursor.advance(lines)
+			return false, printWebLogLines(fresh)
+		})
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	},
+}
+
+// webLogCursor tracks how far 'web logs --follow' has read. The since
+// filter is inclusive, so lines at the boundary timestamp come back on the
+// next poll; they are remembered by message so that only new ones, even
+// with the same timestamp, are printed.
+type webLogCursor struct {
+	since    time.Time
+	boundary map[string]int
+}
+
+// unseen returns the lines not printed yet
+func (c *webLogCursor) unseen(lines []WebLogLine) []WebLogLine {
+	seen := make(map[string]int, len(c.boundary))
+	for msg, n := range c.boundary {
+		seen[msg] = n
+	}
+	var fresh []WebLogLine
+	for _, l := range lines {
+		if l.Timestamp.Before(c.since) {
+			continue
+		}
+		if l.Timestamp.Equal(c.since) && seen[l.Message] > 0 {
+			seen[l.Message]--
+			continue
+		}
+		fresh = append(fresh, l)
+	}
+	return fresh
+}
+
+// advance moves the cursor to the last of lines, which must include every
+// line from the current boundary on
+func (c *webLogCursor) advance(lines []WebLogLine) {
+	if len(lines) == 0 {
+		return
+	}
+	c.since = lines[len(lines)-1].Timestamp
+	c.boundary = make(map[string]int)
+	for _, l := range lines {
+		if l.Timestamp.Equal(c.since) {
+			c.boundary[l.Message]++
+		}
+	}
+}
+
+// getWebInstance looks up a web instance, reporting "not found" only when
+// the API says so
+func getWebInstance(client *Client, id string) (*WebInstance, error) {
+	instance, err := client.GetWebInstance(id)
+	var respErr *ResponseError
+	if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("web instance not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get web instance: %w", err)
+	}
+	return instance, nil
+}
+
+// printWebLogLines prints log lines, as JSON lines with -o json or jsonl
+// and through the template, once per line, with -o template
+func printWebLogLines(lines []WebLogLine) error {
+	for _, l := range lines {
+		switch outputFmt {
+		case "json", "jsonl":
+			if err := OutputJSONL(l); err != nil {
+				return err
+			}
+		case "template":
+			if err := OutputTemplate(l); err != nil {
+				return err
+			}
+		default:
+			fmt.Printf("%s  %s\n", color(ColorGray, formatTime(&l.Timestamp)), l.Message)
+		}
+	}
+	return nil
+}
+
+// webRestartCmd restarts a web instance and waits until it is back online
+var webRestartCmd = &cobra.Command{
+	Use:   "restart <id>",
+	Short: "Restart a web instance",
+	Long: `Restart a web dashboard instance, then poll its health check until it
+reports online again (or --timeout elapses). Use --no-wait to return as
+soon as the restart has been requested.
+
+Examples:
+  vstats web restart <id>
+  vstats web restart <id> --timeout 5m`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireLogin(); err != nil {
+			return err
+		}
+
+		noWait, _ := cmd.Flags().GetBool("no-wait")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		client := NewClient()
+
+		instance, err := getWebInstance(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			printDryRun("POST", "/api/web/instances/"+instance.ID+"/restart", nil, "would restart web instance '%s'", instance.Name)
+			return nil
+		}
+
+		if err := client.RestartWebInstance(instance.ID); err != nil {
+			return fmt.Errorf("failed to restart instance: %w", err)
+		}
+		if !quiet {
+			fmt.Printf("✓ Restart requested for '%s'\n", instance.Name)
+		}
+		if noWait {
+			return nil
+		}
+
+		status, err := waitForWebOnline(client, instance, timeout)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ '%s' is %s (response %s)\n", instance.Name, formatWebStatus(status.Status), status.ResponseTime)
+		return nil
+	},
+}
+
+// waitForWebOnline polls a web instance's health check until it reports
+// online, and returns that status
+func waitForWebOnline(client *Client, instance *WebInstance, timeout time.Duration) (*WebInstanceStatus, error) {
+	var spin *spinner
+	if !quiet {
+		spin = startSpinner(fmt.Sprintf("Waiting for '%s' to come back online...", instance.Name))
+	}
+	var status *WebInstanceStatus
+	err := pollUntil(webRestartPollInterval, timeout, func() (bool, error) {
+		// Failed checks are expected while the instance restarts
+		if s, err := client.CheckWebInstance(instance.ID); err == nil {
+			status = s
+		}
+		return status != nil && status.Status == "online", nil
+	})
+	if spin != nil {
+		spin.Stop()
+	}
+	if errors.Is(err, errPollTimeout) {
+		last := "unreachable"
+		if status != nil {
+			last = formatWebStatus(status.Status)
+		}
+		return nil, fmt.Errorf("'%s' not back online after %s (last status: %s)", instance.Name, timeout, last)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// webUpdateCmd reinstalls a web instance to upgrade it
+var webUpdateCmd = &cobra.Command{
+	Use:   "update <id>",
+	Short: "Upgrade a web instance to the latest (or a pinned) release",
+	Long: `Upgrade a deployed web dashboard by connecting to its host over SSH
+and re-running the install script with the instance's port, domain, and
+SSL settings. The latest release is installed unless --version pins one.
+
+Afterwards the instance's health check is polled until it reports online
+again (or --timeout elapses), and the version it then reports is recorded
+in vStats Cloud.
+
+Examples:
+  vstats web update <id>
+  vstats web update <id> --version 1.4.2
+  vstats web update <id> -u admin -i ~/.ssh/deploy`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireLogin(); err != nil {
+			return err
+		}
+		if err := validateSSHFlags(); err != nil {
+			return err
+		}
+
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		webVersion, _ := cmd.Flags().GetString("version")
+		if webVersion != "" {
+			var err error
+			if webVersion, err = normalizeReleaseVersion(webVersion); err != nil {
+				return err
+			}
+		}
+		client := NewClient()
+
+		instance, err := getWebInstance(client, args[0])
+		if err != nil {
+			return err
+		}
+		if instance.Host == "" {
+			return fmt.Errorf("web instance '%s' has no host recorded", instance.Name)
+		}
+
+		user, host := resolveSSHTarget(instance.Host)
+		sshArgs := buildSSHArgs(user, host)
+		installCmd := webInstallCommand(instance.Port, webInstanceDomain(instance), instance.SSLEnabled, webVersion)
+
+		if dryRun {
+			printDryRun("PUT", "/api/web/instances/"+instance.ID, nil,
+				"would reinstall web dashboard '%s' on %s and record its new version", instance.Name, host)
+			fmt.Printf("  remote command: %s\n", redactToken(installCmd))
+			fmt.Printf("  ssh argv: %s\n", redactToken(formatSSHInvocation(sshArgs, installCmd)))
+			return nil
+		}
+
+		if !quiet {
+			target := "latest"
+			if webVersion != "" {
+				target = webVersion
+			}
+			fmt.Printf("Updating web dashboard '%s' on %s (current: %s, target: %s)...\n",
+				instance.Name, host, orDash(instance.Version), target)
+			fmt.Printf("Checking SSH access to %s...\n", host)
+		}
+		if err := sshPreflight(sshArgs); err != nil {
+			return err
+		}
+		if err := runSSHCommand(sshArgs, installCmd); err != nil {
+			return fmt.Errorf("update failed: %w", err)
+		}
+
+		// Record what the dashboard reports once it is back, falling back
+		// to the pin
+		status, err := waitForWebOnline(client, instance, timeout)
+		if err != nil {
+			return fmt.Errorf("dashboard reinstalled, but %w", err)
+		}
+		version := webVersion
+		if status.Version != "" {
+			version = status.Version
+		}
+		if version != "" {
+			instance.Version = version
+			if err := client.UpdateWebInstance(instance); err != nil {
+				return fmt.Errorf("dashboard updated but failed to record the version: %w", err)
+			}
+		}
+
+		if !quiet {
+			fmt.Println()
+		}
+		fmt.Printf("✓ Web dashboard '%s' updated to %s\n", instance.Name, orDash(version))
+		return nil
+	},
+}
+
+// webInstanceDomain returns the custom domain of an instance: the host of
+// its URL when that differs from the host it is installed on
+func webInstanceDomain(instance *WebInstance) string {
+	u, err := url.Parse(instance.URL)
+	if err != nil || u.Hostname() == instance.Host {
+		return ""
+	}
+	return u.Hostname()
+}
+
+func init() {
+	webCmd.AddCommand(webLogsCmd)
+	webCmd.AddCommand(webRestartCmd)
+	webCmd.AddCommand(webUpdateCmd)
+
+	webLogsCmd.Flags().Int("tail", 100, "number of recent lines to show")
+	webLogsCmd.Flags().BoolP("follow", "f", false, "keep printing new lines until Ctrl-C")
+
+	webRestartCmd.Flags().Bool("no-wait", false, "don't wait for the instance to come back online")
+	webRestartCmd.Flags().Duration("timeout", 2*time.Minute, "give up waiting after this long")
+
+	webUpdateCmd.Flags().StringVarP(&sshUser, "user", "u", "", "SSH username (default: root)")
+	webUpdateCmd.Flags().IntVarP(&sshPort, "port", "p", 0, "SSH port (uses ssh config default)")
+	webUpdateCmd.Flags().StringVarP(&sshKey, "key", "i", "", "SSH private key path")
+	webUpdateCmd.Flags().StringVarP(&sshJump, "jump", "J", "", "Connect through a jump host (user@bastion[:port])")
+	webUpdateCmd.Flags().StringArrayVar(&sshOptions, "ssh-option", nil, "Extra ssh -o option as Key=Value (repeatable)")
+	webUpdateCmd.Flags().String("version", "", "release to install, e.g. 1.4.2 (default: latest)")
+	webUpdateCmd.Flags().Duration("timeout", 2*time.Minute, "give up waiting for the dashboard to come back after this long")
+}
@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWebLogCursorKeepsSameTimestampLines(t *testing.T) {
+	t0 := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	t1 := t0.Add(time.Second)
+	line := func(ts time.Time, msg string) WebLogLine {
+		return WebLogLine{Timestamp: ts, Message: msg}
+	}
+
+	var cursor webLogCursor
+	cursor.advance([]WebLogLine{line(t0, "start"), line(t1, "a")})
+
+	// The inclusive since filter returns "a" again, along with a new line
+	// logged in the same second
+	poll := []WebLogLine{line(t1, "a"), line(t1, "b")}
+	if got, want := cursor.unseen(poll), []WebLogLine{line(t1, "b")}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unseen = %v, want %v", got, want)
+	}
+	cursor.advance(poll)
+
+	// A repeated message in the same second is new too
+	poll = []WebLogLine{line(t1, "a"), line(t1, "b"), line(t1, "a")}
+	if got, want := cursor.unseen(poll), []WebLogLine{line(t1, "a")}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unseen = %v, want %v", got, want)
+	}
+	cursor.advance(poll)
+
+	if got := cursor.unseen(poll); len(got) != 0 {
+		t.Fatalf("unseen = %v, want nothing", got)
+	}
+}
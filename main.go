@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/zsai001/vstats-cli/internal/commands"
@@ -13,7 +12,7 @@ func main() {
 	commands.SetVersion(Version)
 
 	if err := commands.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		commands.ReportError(err)
+		os.Exit(commands.ExitCode(err))
 	}
 }